@@ -1,36 +1,217 @@
 package main
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
 	"stock_backend/config"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/delivery/router"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/mailer"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"stock_backend/internal/worker"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
 	// Load local environment variables
 	config.LoadEnv(".env")
+	cfg := config.MustLoad()
+	logging.Setup(cfg.Log.Level)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Exporter)
+	if err != nil {
+		logging.Fatal("setup tracing failed", "error", err)
+	}
 
 	// Connect to database
-	db := config.DatabaseConfig()
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("[ERROR] error: %v", err)
-		}
-	}()
+	db := config.DatabaseConfig(cfg.Database)
+	redisDb := config.ConnectRedis(cfg.Redis)
 
-	redisDb := config.ConnectRedis()
-	defer func() {
-		if err := redisDb.Close(); err != nil {
-			log.Printf("[ERROR] error: %v", err)
-		}
-	}()
+	// Background work runs until it is stopped explicitly, so it keeps serving
+	// the requests that are still draining after the signal
+	workerCtx := context.Background()
+
+	redisCache := cache.New(
+		redisDb,
+		cfg.Cache.Timeout,
+		cfg.Cache.RetryInterval,
+	)
+	redisCache.Start(workerCtx)
+
+	emailSender, err := mailer.NewSender(cfg.Email)
+	if err != nil {
+		logging.Fatal("load email transport failed", "error", err)
+	}
+
+	// The routes and the alert worker share one client, and with it one breaker
+	stockClient := router.NewStockCache(cfg, redisCache)
 
 	// Routes Grouping
-	app := router.SetupRouter(db, redisDb)
+	app := router.SetupRouter(cfg, db, redisCache, emailSender, stockClient)
+	metricsApp := router.SetupMetricsRouter(db)
+
+	// Background workers
+	emailDispatcher := worker.NewEmailDispatcher(
+		repository.NewEmailOutboxRepository(db),
+		emailSender,
+		cfg.Workers.EmailDispatcherInterval,
+	)
+	emailDispatcher.Start(workerCtx)
+
+	notificationRepository := repository.NewNotificationRepository(db)
+	deliveryRepository := repository.NewNotificationDeliveryRepository(db)
+	notificationWorker := worker.NewNotificationWorker(
+		deliveryRepository,
+		cfg.Workers.NotificationInterval,
+		notificationChannels(cfg, db, emailSender)...,
+	)
+	notificationWorker.Start(workerCtx)
+
+	alertWorker := worker.NewAlertWorker(
+		repository.NewAlertRepository(db),
+		stockClient,
+		notifier.NewQueueNotifier(notificationRepository, deliveryRepository),
+		redisDb,
+		cfg.Workers.AlertInterval,
+	)
+	alertWorker.Start(workerCtx)
 
 	// Run the app
-	if err := app.Listen(":" + os.Getenv("APP_PORT")); err != nil {
-		log.Fatal(err.Error())
+	listenErr := make(chan error, 2)
+	go func() {
+		listenErr <- app.Listen(":" + cfg.App.Port)
+	}()
+	go func() {
+		listenErr <- metricsApp.Listen(":" + cfg.App.MetricsPort)
+	}()
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err := <-listenErr:
+		slog.Error("listen failed", "error", err)
+	}
+
+	// A second signal kills the process without waiting for the drain
+	stop()
+
+	shutdown(shutdownSteps{
+		app:     app,
+		metrics: metricsApp,
+		budget:  cfg.App.ShutdownTimeout,
+		workers: []stopper{alertWorker, notificationWorker, emailDispatcher},
+		cache:   redisCache,
+		sender:  emailSender,
+		db:      db,
+		redisDb: redisDb,
+		tracing: shutdownTracing,
+	})
+}
+
+type stopper interface {
+	Stop(ctx context.Context) error
+}
+
+type shutdownSteps struct {
+	app     *fiber.App
+	metrics *fiber.App
+	budget  time.Duration
+	workers []stopper
+	cache   *cache.Cache
+	sender  mailer.EmailSender
+	db      *sql.DB
+	redisDb *redis.Client
+	tracing func(ctx context.Context) error
+}
+
+// shutdown stops the service in dependency order within one budget: the HTTP
+// server drains its requests, the workers finish the batch in progress, the
+// cache flushes its queued invalidations, and only then are the connections closed
+func shutdown(steps shutdownSteps) {
+	ctx, cancel := context.WithTimeout(context.Background(), steps.budget)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	if err := steps.app.ShutdownWithTimeout(time.Until(deadline)); err != nil {
+		slog.Error("shutdown server failed", "error", err)
+	}
+	// Metrics stay scrapeable while the API drains
+	if err := steps.metrics.ShutdownWithTimeout(time.Until(deadline)); err != nil {
+		slog.Error("shutdown metrics server failed", "error", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, worker := range steps.workers {
+		wg.Add(1)
+		go func(worker stopper) {
+			defer wg.Done()
+			if err := worker.Stop(ctx); err != nil {
+				slog.Error("stop worker failed", "worker", fmt.Sprintf("%T", worker), "error", err)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if err := steps.cache.Stop(ctx); err != nil {
+		slog.Error("stop cache failed", "error", err)
+	}
+
+	if closer, ok := steps.sender.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			slog.Error("close email transport failed", "error", err)
+		}
+	}
+
+	if err := steps.db.Close(); err != nil {
+		slog.Error("close database failed", "error", err)
 	}
+
+	if err := steps.redisDb.Close(); err != nil {
+		slog.Error("close redis failed", "error", err)
+	}
+
+	// Last, so the spans of the drain are exported too
+	if err := steps.tracing(ctx); err != nil {
+		slog.Error("flush traces failed", "error", err)
+	}
+	slog.Info("shutdown complete")
+}
+
+// notificationChannels builds the delivery channels that are configured
+func notificationChannels(cfg *config.Config, db *sql.DB, emailSender mailer.EmailSender) []notifier.Channel {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	channels := []notifier.Channel{
+		notifier.NewInAppChannel(repository.NewNotificationRepository(db)),
+		notifier.NewWebhookChannel(notifier.NewWebhookHTTPClient(10*time.Second), cfg.Notifications.WebhookSecret),
+		notifier.NewEmailChannel(emailSender, cfg.Email.ListUnsubscribe),
+	}
+
+	if apns := cfg.Notifications.APNs; apns.Topic != "" {
+		signer, err := notifier.LoadAPNsTokenSigner(apns.KeyFile, apns.KeyID, apns.TeamID)
+		// APNs is configured, deliveries queued for it would fail until the key is fixed
+		if err != nil {
+			logging.Fatal("load APNs key failed", "error", err)
+		}
+
+		channels = append(channels, notifier.NewAPNsChannel(httpClient, apns.URL, apns.Topic, signer))
+	}
+
+	return channels
 }
@@ -0,0 +1,580 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is every setting of the service, loaded once at startup by Load and
+// handed to the packages that need it
+type Config struct {
+	App           App           `yaml:"app"`
+	Log           Log           `yaml:"log"`
+	Tracing       Tracing       `yaml:"tracing"`
+	Database      Database      `yaml:"database"`
+	Redis         Redis         `yaml:"redis"`
+	JWT           JWT           `yaml:"jwt"`
+	Email         Email         `yaml:"email"`
+	StockService  StockService  `yaml:"stock_service"`
+	Cache         Cache         `yaml:"cache"`
+	Workers       Workers       `yaml:"workers"`
+	Health        Health        `yaml:"health"`
+	Notifications Notifications `yaml:"notifications"`
+	// CircuitBreakers is keyed by breaker name in the form of its variables,
+	// STOCK_SERVICE for the stock-service breaker
+	CircuitBreakers map[string]CircuitBreaker `yaml:"circuit_breakers"`
+}
+
+type App struct {
+	// Host and Port are also used to build the links in outgoing emails
+	Host            string        `yaml:"host"`
+	Port            string        `yaml:"port"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// MetricsPort serves /metrics apart from the API so it is never reachable through the gateway
+	MetricsPort string `yaml:"metrics_port"`
+}
+
+type Log struct {
+	Level string `yaml:"level"`
+}
+
+type Tracing struct {
+	Exporter string `yaml:"exporter"`
+}
+
+type Database struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+}
+
+type Redis struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Password string `yaml:"password"`
+}
+
+type JWT struct {
+	Secret string `yaml:"secret"`
+}
+
+type Email struct {
+	Transport string `yaml:"transport"`
+	From      string `yaml:"from"`
+	FromName  string `yaml:"from_name"`
+	FileDir   string `yaml:"file_dir"`
+	// SecretKey signs the verification links
+	SecretKey       string `yaml:"secret_key"`
+	ListUnsubscribe string `yaml:"list_unsubscribe"`
+	SMTP            SMTP   `yaml:"smtp"`
+}
+
+type SMTP struct {
+	Host        string        `yaml:"host"`
+	Port        string        `yaml:"port"`
+	Email       string        `yaml:"email"`
+	Password    string        `yaml:"password"`
+	TLSMode     string        `yaml:"tls_mode"`
+	DialTimeout time.Duration `yaml:"dial_timeout"`
+	PoolSize    int           `yaml:"pool_size"`
+}
+
+type StockService struct {
+	// URL serves IDX, URLs maps the other exchanges to their service
+	URL  string            `yaml:"url"`
+	URLs map[string]string `yaml:"urls"`
+	// SymbolFormats is the code format of each supported exchange. Configured
+	// entries add exchanges or replace the default format of one.
+	SymbolFormats map[string]string `yaml:"symbol_formats"`
+}
+
+type Cache struct {
+	Timeout          time.Duration `yaml:"timeout"`
+	RetryInterval    time.Duration `yaml:"retry_interval"`
+	WatchlistTTL     time.Duration `yaml:"watchlist_ttl"`
+	StockTTL         time.Duration `yaml:"stock_ttl"`
+	StockNotFoundTTL time.Duration `yaml:"stock_not_found_ttl"`
+	StockStaleTTL    time.Duration `yaml:"stock_stale_ttl"`
+}
+
+type Workers struct {
+	AlertInterval           time.Duration `yaml:"alert_interval"`
+	NotificationInterval    time.Duration `yaml:"notification_interval"`
+	EmailDispatcherInterval time.Duration `yaml:"email_dispatcher_interval"`
+}
+
+type Health struct {
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+}
+
+type Notifications struct {
+	WebhookSecret string `yaml:"webhook_secret"`
+	APNs          APNs   `yaml:"apns"`
+}
+
+// CircuitBreaker tunes the breaker of one dependency, a zero value keeps the default
+type CircuitBreaker struct {
+	MaxRequests  int           `yaml:"max_requests"`
+	Interval     time.Duration `yaml:"interval"`
+	Timeout      time.Duration `yaml:"timeout"`
+	MinRequests  int           `yaml:"min_requests"`
+	FailureRatio float64       `yaml:"failure_ratio"`
+}
+
+// CircuitBreakerKey returns the form of a breaker name used in its variables
+// and as key of Config.CircuitBreakers, STOCK_SERVICE for stock-service
+func CircuitBreakerKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// CircuitBreaker returns the settings of the named breaker
+func (cfg *Config) CircuitBreaker(name string) CircuitBreaker {
+	return cfg.CircuitBreakers[CircuitBreakerKey(name)]
+}
+
+// APNs is enabled when Topic is set
+type APNs struct {
+	URL     string `yaml:"url"`
+	Topic   string `yaml:"topic"`
+	KeyFile string `yaml:"key_file"`
+	KeyID   string `yaml:"key_id"`
+	TeamID  string `yaml:"team_id"`
+}
+
+// Default returns the settings used for everything that is not configured
+func Default() *Config {
+	return &Config{
+		App:     App{ShutdownTimeout: 15 * time.Second, MetricsPort: "9090"},
+		Log:     Log{Level: "info"},
+		Tracing: Tracing{Exporter: "none"},
+		Email: Email{
+			Transport: "smtp",
+			FromName:  "Stock App",
+			FileDir:   "./mail",
+			SMTP: SMTP{
+				TLSMode:     "starttls",
+				DialTimeout: 10 * time.Second,
+				PoolSize:    2,
+			},
+		},
+		StockService: StockService{
+			SymbolFormats: map[string]string{
+				// Shares such as BBCA plus warrants (BUKA-W) and rights (BBRI-R)
+				"IDX":    `^[A-Z]{4}(-[WR])?$`,
+				"NASDAQ": `^[A-Z]{1,5}$`,
+				"NYSE":   `^[A-Z]{1,4}(\.[A-Z])?$`,
+			},
+		},
+		Cache: Cache{
+			Timeout:          200 * time.Millisecond,
+			RetryInterval:    5 * time.Second,
+			WatchlistTTL:     5 * time.Minute,
+			StockTTL:         24 * time.Hour,
+			StockNotFoundTTL: time.Minute,
+			StockStaleTTL:    7 * 24 * time.Hour,
+		},
+		Workers: Workers{
+			AlertInterval:           30 * time.Second,
+			NotificationInterval:    10 * time.Second,
+			EmailDispatcherInterval: 5 * time.Second,
+		},
+		Health: Health{
+			CheckTimeout: time.Second,
+			CacheTTL:     5 * time.Second,
+		},
+		Notifications: Notifications{
+			APNs: APNs{URL: "https://api.push.apple.com"},
+		},
+	}
+}
+
+// Load starts from the defaults, applies the YAML file named by CONFIG_FILE if
+// any, then the environment, and validates the result. Every variable can
+// also be read from a file by setting NAME_FILE instead of NAME, for secrets
+// mounted by the orchestrator.
+func Load() (*Config, error) {
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadYAML(path); err != nil {
+			return nil, err
+		}
+	}
+	// The file may name breakers as they are written, stock-service, and the environment STOCK_SERVICE
+	breakers := make(map[string]CircuitBreaker, len(cfg.CircuitBreakers))
+	for name, breaker := range cfg.CircuitBreakers {
+		breakers[CircuitBreakerKey(name)] = breaker
+	}
+	cfg.CircuitBreakers = breakers
+
+	env := &envLoader{}
+	env.string(&cfg.App.Host, "APP_HOST")
+	env.string(&cfg.App.Port, "APP_PORT")
+	env.duration(&cfg.App.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	env.string(&cfg.App.MetricsPort, "METRICS_PORT")
+	env.string(&cfg.Log.Level, "LOG_LEVEL")
+	env.string(&cfg.Tracing.Exporter, "TRACING_EXPORTER")
+
+	env.string(&cfg.Database.Host, "DB_HOST")
+	env.string(&cfg.Database.Port, "DB_PORT")
+	env.string(&cfg.Database.User, "DB_USER")
+	env.string(&cfg.Database.Password, "DB_PASSWORD")
+	env.string(&cfg.Database.Name, "DB_NAME")
+
+	env.string(&cfg.Redis.Host, "REDIS_HOST")
+	env.int(&cfg.Redis.Port, "REDIS_PORT")
+	env.string(&cfg.Redis.Password, "REDIS_PASSWORD")
+
+	env.string(&cfg.JWT.Secret, "JWT_SECRET")
+
+	env.string(&cfg.Email.Transport, "EMAIL_TRANSPORT")
+	env.string(&cfg.Email.From, "EMAIL_FROM")
+	env.string(&cfg.Email.FromName, "EMAIL_FROM_NAME")
+	env.string(&cfg.Email.FileDir, "EMAIL_FILE_DIR")
+	env.string(&cfg.Email.SecretKey, "EMAIL_SECRET_KEY")
+	env.string(&cfg.Email.ListUnsubscribe, "EMAIL_LIST_UNSUBSCRIBE")
+	env.string(&cfg.Email.SMTP.Host, "SMTP_HOST")
+	env.string(&cfg.Email.SMTP.Port, "SMTP_PORT")
+	env.string(&cfg.Email.SMTP.Email, "SMTP_EMAIL")
+	env.string(&cfg.Email.SMTP.Password, "SMTP_PASSWORD")
+	env.string(&cfg.Email.SMTP.TLSMode, "SMTP_TLS_MODE")
+	env.duration(&cfg.Email.SMTP.DialTimeout, "SMTP_DIAL_TIMEOUT")
+	env.int(&cfg.Email.SMTP.PoolSize, "SMTP_POOL_SIZE")
+
+	env.string(&cfg.StockService.URL, "STOCK_SERVICE_URL")
+	env.pairs(&cfg.StockService.URLs, "STOCK_SERVICE_URLS", "EXCHANGE=URL", false)
+	env.pairs(&cfg.StockService.SymbolFormats, "SYMBOL_FORMATS", "EXCHANGE=regex", true)
+
+	env.duration(&cfg.Cache.Timeout, "CACHE_TIMEOUT")
+	env.duration(&cfg.Cache.RetryInterval, "CACHE_RETRY_INTERVAL")
+	env.duration(&cfg.Cache.WatchlistTTL, "WATCHLIST_CACHE_TTL")
+	env.duration(&cfg.Cache.StockTTL, "STOCK_CACHE_TTL")
+	env.duration(&cfg.Cache.StockNotFoundTTL, "STOCK_CACHE_NOT_FOUND_TTL")
+	env.duration(&cfg.Cache.StockStaleTTL, "STOCK_CACHE_STALE_TTL")
+
+	env.duration(&cfg.Workers.AlertInterval, "ALERT_WORKER_INTERVAL")
+	env.duration(&cfg.Workers.NotificationInterval, "NOTIFICATION_WORKER_INTERVAL")
+	env.duration(&cfg.Workers.EmailDispatcherInterval, "EMAIL_DISPATCHER_INTERVAL")
+
+	env.duration(&cfg.Health.CheckTimeout, "HEALTH_CHECK_TIMEOUT")
+	env.duration(&cfg.Health.CacheTTL, "HEALTH_CACHE_TTL")
+
+	env.string(&cfg.Notifications.WebhookSecret, "NOTIFICATION_WEBHOOK_SECRET")
+	env.string(&cfg.Notifications.APNs.URL, "APNS_URL")
+	env.string(&cfg.Notifications.APNs.Topic, "APNS_TOPIC")
+	env.string(&cfg.Notifications.APNs.KeyFile, "APNS_KEY_FILE")
+	env.string(&cfg.Notifications.APNs.KeyID, "APNS_KEY_ID")
+	env.string(&cfg.Notifications.APNs.TeamID, "APNS_TEAM_ID")
+
+	env.circuitBreakers(cfg.CircuitBreakers)
+
+	// A value that could not be parsed keeps its default, so validating still reports the other problems
+	if err := errors.Join(append(env.errs, cfg.Validate())...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// MustLoad loads the configuration or exits with every problem it found
+func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// loadYAML overrides the defaults with the file at path. Unknown keys are
+// rejected so a misspelled setting does not silently keep its default.
+func (cfg *Config) loadYAML(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read CONFIG_FILE: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse CONFIG_FILE %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every missing or invalid setting at once, named by its environment variable
+func (cfg *Config) Validate() error {
+	var errs []error
+	require := func(value string, key string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	positive := func(value time.Duration, key string) {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", key, value))
+		}
+	}
+	oneOf := func(value string, key string, allowed ...string) {
+		for _, option := range allowed {
+			if value == option {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+
+	require(cfg.App.Host, "APP_HOST")
+	require(cfg.App.Port, "APP_PORT")
+	if _, err := strconv.Atoi(cfg.App.Port); cfg.App.Port != "" && err != nil {
+		errs = append(errs, fmt.Errorf("APP_PORT must be a number, got %q", cfg.App.Port))
+	}
+	positive(cfg.App.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	require(cfg.App.MetricsPort, "METRICS_PORT")
+	if _, err := strconv.Atoi(cfg.App.MetricsPort); cfg.App.MetricsPort != "" && err != nil {
+		errs = append(errs, fmt.Errorf("METRICS_PORT must be a number, got %q", cfg.App.MetricsPort))
+	}
+	if cfg.App.MetricsPort == cfg.App.Port {
+		errs = append(errs, fmt.Errorf("METRICS_PORT must differ from APP_PORT, both are %q", cfg.App.Port))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be one of debug, info, warn, error, got %q", cfg.Log.Level))
+	}
+	oneOf(cfg.Tracing.Exporter, "TRACING_EXPORTER", "none", "otlp", "stdout")
+
+	require(cfg.Database.Host, "DB_HOST")
+	require(cfg.Database.Port, "DB_PORT")
+	require(cfg.Database.User, "DB_USER")
+	require(cfg.Database.Name, "DB_NAME")
+
+	require(cfg.Redis.Host, "REDIS_HOST")
+	if cfg.Redis.Port < 1 || cfg.Redis.Port > 65535 {
+		errs = append(errs, fmt.Errorf("REDIS_PORT must be a port number, got %d", cfg.Redis.Port))
+	}
+
+	// An empty key would sign tokens anyone can forge
+	require(cfg.JWT.Secret, "JWT_SECRET")
+	require(cfg.Email.SecretKey, "EMAIL_SECRET_KEY")
+
+	oneOf(cfg.Email.Transport, "EMAIL_TRANSPORT", "smtp", "file", "memory")
+	if cfg.Email.Transport == "smtp" {
+		require(cfg.Email.SMTP.Host, "SMTP_HOST")
+		require(cfg.Email.SMTP.Port, "SMTP_PORT")
+		if cfg.Email.From == "" {
+			require(cfg.Email.SMTP.Email, "EMAIL_FROM or SMTP_EMAIL")
+		}
+		oneOf(cfg.Email.SMTP.TLSMode, "SMTP_TLS_MODE", "none", "starttls", "implicit")
+		positive(cfg.Email.SMTP.DialTimeout, "SMTP_DIAL_TIMEOUT")
+		if cfg.Email.SMTP.PoolSize < 1 {
+			errs = append(errs, fmt.Errorf("SMTP_POOL_SIZE must be positive, got %d", cfg.Email.SMTP.PoolSize))
+		}
+	}
+
+	require(cfg.StockService.URL, "STOCK_SERVICE_URL")
+	for _, exchange := range slices.Sorted(maps.Keys(cfg.StockService.SymbolFormats)) {
+		if _, err := regexp.Compile(cfg.StockService.SymbolFormats[exchange]); err != nil {
+			errs = append(errs, fmt.Errorf("SYMBOL_FORMATS pattern of %s does not compile: %w", exchange, err))
+		}
+	}
+
+	positive(cfg.Cache.Timeout, "CACHE_TIMEOUT")
+	positive(cfg.Cache.RetryInterval, "CACHE_RETRY_INTERVAL")
+	positive(cfg.Cache.WatchlistTTL, "WATCHLIST_CACHE_TTL")
+	positive(cfg.Cache.StockTTL, "STOCK_CACHE_TTL")
+	positive(cfg.Cache.StockNotFoundTTL, "STOCK_CACHE_NOT_FOUND_TTL")
+	positive(cfg.Cache.StockStaleTTL, "STOCK_CACHE_STALE_TTL")
+
+	positive(cfg.Workers.AlertInterval, "ALERT_WORKER_INTERVAL")
+	positive(cfg.Workers.NotificationInterval, "NOTIFICATION_WORKER_INTERVAL")
+	positive(cfg.Workers.EmailDispatcherInterval, "EMAIL_DISPATCHER_INTERVAL")
+
+	positive(cfg.Health.CheckTimeout, "HEALTH_CHECK_TIMEOUT")
+	positive(cfg.Health.CacheTTL, "HEALTH_CACHE_TTL")
+
+	if cfg.Notifications.APNs.Topic != "" {
+		require(cfg.Notifications.APNs.URL, "APNS_URL")
+		require(cfg.Notifications.APNs.KeyFile, "APNS_KEY_FILE")
+		require(cfg.Notifications.APNs.KeyID, "APNS_KEY_ID")
+		require(cfg.Notifications.APNs.TeamID, "APNS_TEAM_ID")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.CircuitBreakers)) {
+		breaker := cfg.CircuitBreakers[name]
+		prefix := "CIRCUIT_" + CircuitBreakerKey(name) + "_"
+		if breaker.MaxRequests < 0 {
+			errs = append(errs, fmt.Errorf("%sMAX_REQUESTS must not be negative, got %d", prefix, breaker.MaxRequests))
+		}
+		if breaker.MinRequests < 0 {
+			errs = append(errs, fmt.Errorf("%sMIN_REQUESTS must not be negative, got %d", prefix, breaker.MinRequests))
+		}
+		if breaker.Interval < 0 {
+			errs = append(errs, fmt.Errorf("%sINTERVAL must not be negative, got %s", prefix, breaker.Interval))
+		}
+		if breaker.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("%sTIMEOUT must not be negative, got %s", prefix, breaker.Timeout))
+		}
+		if breaker.FailureRatio < 0 || breaker.FailureRatio > 1 {
+			errs = append(errs, fmt.Errorf("%sFAILURE_RATIO must be between 0 and 1, got %g", prefix, breaker.FailureRatio))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// envLoader overrides settings with the environment and collects the values it could not parse
+type envLoader struct {
+	errs []error
+}
+
+// lookup returns the value of key, or the content of the file named by key_FILE
+func (env *envLoader) lookup(key string) (string, bool) {
+	value := os.Getenv(key)
+	file := os.Getenv(key + "_FILE")
+	switch {
+	case value != "" && file != "":
+		env.errs = append(env.errs, fmt.Errorf("%s and %s_FILE are both set", key, key))
+		return "", false
+
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			env.errs = append(env.errs, fmt.Errorf("read %s_FILE: %w", key, err))
+			return "", false
+		}
+		return strings.TrimRight(string(content), "\r\n"), true
+	}
+	return value, value != ""
+}
+
+func (env *envLoader) string(target *string, key string) {
+	if value, ok := env.lookup(key); ok {
+		*target = value
+	}
+}
+
+func (env *envLoader) int(target *int, key string) {
+	value, ok := env.lookup(key)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s must be a number, got %q", key, value))
+		return
+	}
+	*target = number
+}
+
+func (env *envLoader) float(target *float64, key string) {
+	value, ok := env.lookup(key)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s must be a number, got %q", key, value))
+		return
+	}
+	*target = number
+}
+
+func (env *envLoader) duration(target *time.Duration, key string) {
+	value, ok := env.lookup(key)
+	if !ok {
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s must be a duration such as 30s, got %q", key, value))
+		return
+	}
+	*target = duration
+}
+
+// pairs reads entries such as "NASDAQ=http://us-stocks;NYSE=http://us-stocks".
+// With merge the entries add to or replace those of target, otherwise they
+// replace target as a whole.
+func (env *envLoader) pairs(target *map[string]string, key string, format string, merge bool) {
+	value, ok := env.lookup(key)
+	if !ok {
+		return
+	}
+
+	pairs := map[string]string{}
+	if merge {
+		maps.Copy(pairs, *target)
+	}
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		exchange, pairValue, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			env.errs = append(env.errs, fmt.Errorf("%s entries must look like %s, got %q", key, format, entry))
+			continue
+		}
+		pairs[strings.ToUpper(strings.TrimSpace(exchange))] = strings.TrimSpace(pairValue)
+	}
+	*target = pairs
+}
+
+// circuitBreakerSettings are the variables of a breaker, CIRCUIT_<NAME> followed by one of them
+var circuitBreakerSettings = []string{"_MAX_REQUESTS", "_MIN_REQUESTS", "_INTERVAL", "_TIMEOUT", "_FAILURE_RATIO"}
+
+// circuitBreakers reads the CIRCUIT_<NAME>_* variables of every breaker named in the environment
+func (env *envLoader) circuitBreakers(target map[string]CircuitBreaker) {
+	seen := map[string]bool{}
+	for _, variable := range os.Environ() {
+		key, _, _ := strings.Cut(variable, "=")
+		key = strings.TrimSuffix(key, "_FILE")
+		if !strings.HasPrefix(key, "CIRCUIT_") || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var name, setting string
+		for _, suffix := range circuitBreakerSettings {
+			if trimmed, found := strings.CutSuffix(strings.TrimPrefix(key, "CIRCUIT_"), suffix); found && trimmed != "" {
+				name, setting = trimmed, suffix
+				break
+			}
+		}
+		if name == "" {
+			env.errs = append(env.errs, fmt.Errorf("%s is not a circuit breaker setting, want CIRCUIT_<NAME>%s", key, strings.Join(circuitBreakerSettings, ", ")))
+			continue
+		}
+
+		breaker := target[name]
+		switch setting {
+		case "_MAX_REQUESTS":
+			env.int(&breaker.MaxRequests, key)
+		case "_MIN_REQUESTS":
+			env.int(&breaker.MinRequests, key)
+		case "_INTERVAL":
+			env.duration(&breaker.Interval, key)
+		case "_TIMEOUT":
+			env.duration(&breaker.Timeout, key)
+		case "_FAILURE_RATIO":
+			env.float(&breaker.FailureRatio, key)
+		}
+		target[name] = breaker
+	}
+}
@@ -1,13 +1,13 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/joho/godotenv"
 )
 
 func LoadEnv(filename string) {
 	if err := godotenv.Load(filename); err != nil {
-		log.Printf("[ERROR] error: %v", err)
+		slog.Error("load env file failed", "error", err)
 	}
 }
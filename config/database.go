@@ -2,32 +2,34 @@ package config
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"stock_backend/internal/logging"
 	"time"
 
 	"database/sql"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
-func DatabaseConfig() *sql.DB {
+func DatabaseConfig(cfg Database) *sql.DB {
 	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
 	)
 
-	db, err := sql.Open("postgres", dsn)
+	// Queries are traced as children of the span in their context
+	db, err := otelsql.Open("postgres", dsn, otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL))
 	if err != nil {
-		log.Fatalf("failed to connect to DB: %v", err)
+		logging.Fatal("connect database failed", "error", err)
 	}
 
 	if err := db.Ping(); err != nil {
-		log.Fatalf("failed to connect to DB: %v", err)
+		logging.Fatal("connect database failed", "error", err)
 	}
 
 	db.SetMaxIdleConns(28)
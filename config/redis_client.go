@@ -2,30 +2,33 @@ package config
 
 import (
 	"context"
-	"log"
-	"os"
+	"net"
+	"stock_backend/internal/logging"
+	"strconv"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
-func ConnectRedis() *redis.Client {
+func ConnectRedis(cfg Redis) *redis.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-	redisPass := os.Getenv("REDIS_PASSWORD")
-
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisHost + ":" + redisPort,
-		Password: redisPass,
+		Addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Password: cfg.Password,
 		DB:       0,
 	})
 
+	// Commands are traced as children of the span in their context
+	if err := redisotel.InstrumentTracing(redisClient); err != nil {
+		logging.Fatal("connect redis failed", "error", err)
+	}
+
 	// Test Connection
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Fatal(err.Error())
+		logging.Fatal("connect redis failed", "error", err)
 	}
 
 	return redisClient
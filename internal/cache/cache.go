@@ -0,0 +1,283 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"stock_backend/internal/logging"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Keys waiting to be invalidated beyond this are dropped; their TTL still bounds how stale they get
+	maxPendingInvalidations = 10000
+	retryBatchSize          = 500
+)
+
+// ErrDegraded is returned instead of calling Redis while the cache is degraded
+var ErrDegraded = errors.New("cache is degraded")
+
+// Cache wraps Redis so callers can treat it as optional. Every call is
+// time-boxed, a failed read is reported as a miss so callers fall back to
+// Postgres, and a failed invalidation is queued and retried until Redis is back.
+//
+// The first failure marks the cache degraded. While degraded, reads miss and
+// writes are skipped without calling Redis, so an outage does not add the
+// timeout to every request. The cache is trusted again only after Redis answers
+// and every queued invalidation has been applied.
+type Cache struct {
+	client        *redis.Client
+	timeout       time.Duration
+	retryInterval time.Duration
+
+	degraded atomic.Bool
+	mu       sync.Mutex
+	pending  map[string]struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New wraps client and instruments it, so it must be called once per client
+func New(client *redis.Client, timeout time.Duration, retryInterval time.Duration) *Cache {
+	client.AddHook(metricsHook{})
+	return &Cache{
+		client:        client,
+		timeout:       timeout,
+		retryInterval: retryInterval,
+		pending:       map[string]struct{}{},
+	}
+}
+
+// Degraded reports whether the cache is bypassed because Redis failed
+func (cache *Cache) Degraded() bool {
+	return cache.degraded.Load()
+}
+
+// PendingInvalidations returns the number of keys waiting to be invalidated
+func (cache *Cache) PendingInvalidations() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return len(cache.pending)
+}
+
+// Ping checks that Redis answers within the cache timeout. It does not change
+// the degraded flag, recovering is left to the retry loop.
+func (cache *Cache) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, cache.timeout)
+	defer cancel()
+	return cache.client.Ping(ctx).Err()
+}
+
+// PoolStats returns the connection pool statistics of the Redis client
+func (cache *Cache) PoolStats() *redis.PoolStats {
+	return cache.client.PoolStats()
+}
+
+// Do runs fn against Redis within the cache timeout. A redis.Nil result is a
+// miss, any other error marks the cache degraded unless the caller's own
+// context was canceled or expired first.
+func (cache *Cache) Do(ctx context.Context, fn func(ctx context.Context, client *redis.Client) error) error {
+	if cache.Degraded() {
+		return ErrDegraded
+	}
+	return cache.do(ctx, fn)
+}
+
+func (cache *Cache) do(parent context.Context, fn func(ctx context.Context, client *redis.Client) error) error {
+	ctx, cancel := context.WithTimeout(parent, cache.timeout)
+	defer cancel()
+
+	err := fn(ctx, cache.client)
+	// An error after the caller gave up says nothing about the health of Redis
+	if err != nil && !errors.Is(err, redis.Nil) && parent.Err() == nil {
+		cache.markDegraded(err)
+	}
+	return err
+}
+
+// Get returns the value of a key and whether it was found
+func (cache *Cache) Get(ctx context.Context, key string) (string, bool) {
+	var value string
+	err := cache.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		var err error
+		value, err = client.Get(ctx, key).Result()
+		return err
+	})
+	return value, err == nil
+}
+
+// HGet returns the value of a hash field and whether it was found
+func (cache *Cache) HGet(ctx context.Context, key string, field string) (string, bool) {
+	var value string
+	err := cache.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		var err error
+		value, err = client.HGet(ctx, key, field).Result()
+		return err
+	})
+	return value, err == nil
+}
+
+// Set stores a value. Failures are logged and otherwise ignored, the next read simply misses.
+func (cache *Cache) Set(ctx context.Context, key string, value any, ttl time.Duration) {
+	_ = cache.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.Set(ctx, key, value, ttl).Err()
+	})
+}
+
+// Invalidate deletes keys, queueing them for retry when Redis cannot be reached
+func (cache *Cache) Invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if cache.queueIfDegraded(keys...) {
+		return
+	}
+
+	// A stale entry outlives the request, so the delete must not be abandoned with it
+	err := cache.do(context.WithoutCancel(ctx), func(ctx context.Context, client *redis.Client) error {
+		return client.Del(ctx, keys...).Err()
+	})
+	if err != nil {
+		cache.queue(keys...)
+	}
+}
+
+func (cache *Cache) Start(ctx context.Context) {
+	ctx, cache.cancel = context.WithCancel(ctx)
+	cache.done = make(chan struct{})
+
+	go cache.run(ctx)
+}
+
+// Stop ends the retry loop and makes a last attempt to apply the queued
+// invalidations, so entries changed before shutdown do not outlive it
+func (cache *Cache) Stop(ctx context.Context) error {
+	if cache.cancel == nil {
+		return nil
+	}
+	cache.cancel()
+
+	select {
+	case <-cache.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if cache.Degraded() {
+		cache.Recover(ctx)
+	}
+
+	if pending := cache.PendingInvalidations(); pending > 0 {
+		logging.Error(ctx, "flush cache invalidations failed", "pending", pending)
+	}
+	return nil
+}
+
+func (cache *Cache) run(ctx context.Context) {
+	defer close(cache.done)
+
+	ticker := time.NewTicker(cache.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if cache.Degraded() {
+				cache.Recover(ctx)
+			}
+		}
+	}
+}
+
+// Recover retries the queued invalidations and clears the degraded flag once
+// Redis answers and nothing is left to invalidate
+func (cache *Cache) Recover(ctx context.Context) {
+	if err := cache.do(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.Ping(ctx).Err()
+	}); err != nil {
+		return
+	}
+
+	for {
+		keys := cache.takePending(retryBatchSize)
+		if len(keys) == 0 {
+			break
+		}
+
+		if err := cache.do(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.Del(ctx, keys...).Err()
+		}); err != nil {
+			cache.queue(keys...)
+			return
+		}
+	}
+
+	// Keys queued while the queue was drained keep the cache degraded until the next attempt
+	cache.mu.Lock()
+	recovered := len(cache.pending) == 0 && cache.degraded.CompareAndSwap(true, false)
+	cache.mu.Unlock()
+
+	if recovered {
+		logging.Info(ctx, "redis cache recovered")
+	}
+}
+
+func (cache *Cache) markDegraded(err error) {
+	if !cache.degraded.Swap(true) {
+		slog.Error("redis cache degraded", "error", err)
+	}
+}
+
+func (cache *Cache) queueIfDegraded(keys ...string) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.degraded.Load() {
+		return false
+	}
+	cache.queueLocked(keys...)
+	return true
+}
+
+func (cache *Cache) queue(keys ...string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.queueLocked(keys...)
+}
+
+// queueLocked keeps the cache degraded while keys are pending so it is not
+// trusted before they are invalidated
+func (cache *Cache) queueLocked(keys ...string) {
+	cache.degraded.Store(true)
+	for _, key := range keys {
+		if len(cache.pending) >= maxPendingInvalidations {
+			slog.Error("cache invalidation queue is full, dropping key", "key", key)
+			continue
+		}
+		cache.pending[key] = struct{}{}
+	}
+}
+
+func (cache *Cache) takePending(limit int) []string {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	keys := make([]string, 0, min(limit, len(cache.pending)))
+	for key := range cache.pending {
+		if len(keys) == limit {
+			break
+		}
+		keys = append(keys, key)
+		delete(cache.pending, key)
+	}
+	return keys
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "redis_command_duration_seconds",
+		Help:    "Latency of Redis commands by command and status. Pipelines are reported as one pipeline command.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command", "status"})
+
+	lookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Cache reads by cache and result: hit, miss, or degraded when Redis was bypassed.",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(commandDuration, lookups)
+}
+
+// RecordLookup counts a read of the named cache, such as favorites or watchlist
+func (cache *Cache) RecordLookup(name string, hit bool) {
+	result := "miss"
+	switch {
+	case hit:
+		result = "hit"
+	case cache.Degraded():
+		result = "degraded"
+	}
+	lookups.WithLabelValues(name, result).Inc()
+}
+
+// metricsHook times every command sent through the Redis client
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(strings.ToLower(cmd.Name()), start, err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", start, err)
+		return err
+	}
+}
+
+func observeCommand(command string, start time.Time, err error) {
+	status := "ok"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		status = "error"
+	}
+	commandDuration.WithLabelValues(command, status).Observe(time.Since(start).Seconds())
+}
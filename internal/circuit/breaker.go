@@ -1,26 +1,233 @@
 package circuit
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"stock_backend/config"
+	"stock_backend/internal/model/domainerr"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sony/gobreaker"
 )
 
-func NewCircuitBreaker(name string) *gobreaker.CircuitBreaker {
-	setting := gobreaker.Settings{
-		Name:        name,
-		MaxRequests: 5, // Max request in Half-Open state
-		Interval:    10 * time.Second,
-		Timeout:     30 * time.Second, // Open state time
+// Settings tune a breaker. Every dependency has its own in
+// config.Config.CircuitBreakers, for example CIRCUIT_STOCK_SERVICE_TIMEOUT.
+type Settings struct {
+	MaxRequests  uint32        // Max request in Half-Open state
+	Interval     time.Duration // Period after which the counts of the Closed state are cleared
+	Timeout      time.Duration // Open state time
+	MinRequests  uint32        // Requests needed in an interval before the breaker may trip
+	FailureRatio float64       // The breaker trips when the failure ratio is above this
+}
+
+var DefaultSettings = Settings{
+	MaxRequests:  5,
+	Interval:     10 * time.Second,
+	Timeout:      30 * time.Second,
+	MinRequests:  5,
+	FailureRatio: 0.5,
+}
+
+// NewSettings applies the configured settings of a breaker, keeping the defaults for anything unset
+func NewSettings(cfg config.CircuitBreaker) Settings {
+	settings := DefaultSettings
+	if cfg.MaxRequests > 0 {
+		settings.MaxRequests = uint32(cfg.MaxRequests)
+	}
+	if cfg.Interval > 0 {
+		settings.Interval = cfg.Interval
+	}
+	if cfg.Timeout > 0 {
+		settings.Timeout = cfg.Timeout
+	}
+	if cfg.MinRequests > 0 {
+		settings.MinRequests = uint32(cfg.MinRequests)
+	}
+	if cfg.FailureRatio > 0 {
+		settings.FailureRatio = cfg.FailureRatio
+	}
+	return settings
+}
+
+// Override pins a breaker in a state regardless of the traffic it sees
+type Override int32
+
+const (
+	OverrideNone Override = iota
+	OverrideOpen
+	OverrideClosed
+)
+
+func (override Override) String() string {
+	switch override {
+	case OverrideOpen:
+		return "open"
+	case OverrideClosed:
+		return "closed"
+	default:
+		return "none"
+	}
+}
+
+// ParseOverride reads an override as written by String
+func ParseOverride(value string) (Override, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "open":
+		return OverrideOpen, nil
+	case "closed":
+		return OverrideClosed, nil
+	case "none":
+		return OverrideNone, nil
+	default:
+		return OverrideNone, domainerr.ErrCircuitOverrideInvalid
+	}
+}
+
+// Breaker is a gobreaker.CircuitBreaker that can be forced open or closed during an incident
+type Breaker struct {
+	name     string
+	settings Settings
+	breaker  atomic.Pointer[gobreaker.CircuitBreaker]
+	override atomic.Int32
+}
+
+var registry = struct {
+	sync.Mutex
+	breakers map[string]*Breaker
+}{breakers: map[string]*Breaker{}}
+
+// NewCircuitBreaker returns the breaker of a dependency. Callers that name the
+// same dependency share one breaker, so its state reflects all of their traffic,
+// and the settings of the first caller apply.
+func NewCircuitBreaker(name string, cfg config.CircuitBreaker) *Breaker {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if breaker, ok := registry.breakers[name]; ok {
+		return breaker
+	}
+
+	breaker := &Breaker{
+		name:     name,
+		settings: NewSettings(cfg),
+	}
+	breaker.breaker.Store(breaker.newGoBreaker())
+	registry.breakers[name] = breaker
+	return breaker
+}
+
+// Breakers returns every registered breaker ordered by name
+func Breakers() []*Breaker {
+	registry.Lock()
+	defer registry.Unlock()
+
+	breakers := make([]*Breaker, 0, len(registry.breakers))
+	for _, breaker := range registry.breakers {
+		breakers = append(breakers, breaker)
+	}
+
+	sort.Slice(breakers, func(i, j int) bool {
+		return breakers[i].name < breakers[j].name
+	})
+	return breakers
+}
+
+// GetBreaker returns the registered breaker with the given name
+func GetBreaker(name string) (*Breaker, error) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	breaker, ok := registry.breakers[name]
+	if !ok {
+		return nil, domainerr.ErrCircuitBreakerNotFound
+	}
+	return breaker, nil
+}
+
+func (breaker *Breaker) newGoBreaker() *gobreaker.CircuitBreaker {
+	settings := breaker.settings
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        breaker.name,
+		MaxRequests: settings.MaxRequests,
+		Interval:    settings.Interval,
+		Timeout:     settings.Timeout,
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
-			return counts.Requests >= 5 && failureRatio > 0.5
+			return counts.Requests >= settings.MinRequests && failureRatio > settings.FailureRatio
+		},
+		// A 4xx answer means the service is healthy and only the request was wrong,
+		// and a request the caller abandoned says nothing about the service
+		IsSuccessful: func(err error) bool {
+			var serviceErr *domainerr.ServiceError
+			if errors.As(err, &serviceErr) {
+				return serviceErr.Code < http.StatusInternalServerError
+			}
+			return err == nil || errors.Is(err, context.Canceled)
 		},
 		OnStateChange: func(name string, from, to gobreaker.State) {
-			log.Printf("[Circuit breaker] %s : %s → %s", name, from.String(), to.String())
+			slog.Warn("circuit breaker state changed", "breaker", name, "from", from.String(), "to", to.String())
+			stateChanges.WithLabelValues(name, from.String(), to.String()).Inc()
 		},
+	})
+}
+
+// Execute runs req unless the breaker is open. A breaker forced closed runs
+// every request and does not count its outcome.
+func (breaker *Breaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	switch breaker.Override() {
+	case OverrideOpen:
+		return nil, gobreaker.ErrOpenState
+	case OverrideClosed:
+		return req()
+	default:
+		return breaker.breaker.Load().Execute(req)
+	}
+}
+
+func (breaker *Breaker) Name() string {
+	return breaker.name
+}
+
+func (breaker *Breaker) Settings() Settings {
+	return breaker.settings
+}
+
+// State returns the state requests currently see, taking the override into account
+func (breaker *Breaker) State() gobreaker.State {
+	switch breaker.Override() {
+	case OverrideOpen:
+		return gobreaker.StateOpen
+	case OverrideClosed:
+		return gobreaker.StateClosed
+	default:
+		return breaker.breaker.Load().State()
+	}
+}
+
+func (breaker *Breaker) Counts() gobreaker.Counts {
+	return breaker.breaker.Load().Counts()
+}
+
+func (breaker *Breaker) Override() Override {
+	return Override(breaker.override.Load())
+}
+
+// SetOverride forces the breaker open or closed. Clearing the override starts
+// the breaker again from a closed state with empty counts.
+func (breaker *Breaker) SetOverride(override Override) {
+	previous := Override(breaker.override.Swap(int32(override)))
+	if previous == override {
+		return
 	}
 
-	return gobreaker.NewCircuitBreaker(setting)
+	if override == OverrideNone {
+		breaker.breaker.Store(breaker.newGoBreaker())
+	}
+	slog.Warn("circuit breaker override changed", "breaker", breaker.name, "from", previous, "to", override)
 }
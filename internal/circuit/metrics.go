@@ -0,0 +1,84 @@
+package circuit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+)
+
+var (
+	stateChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "circuit_breaker_state_changes_total",
+		Help: "Number of state transitions of each circuit breaker.",
+	}, []string{"name", "from", "to"})
+
+	stateDesc = prometheus.NewDesc(
+		"circuit_breaker_state",
+		"Current state of each circuit breaker: 0 closed, 1 half-open, 2 open.",
+		[]string{"name"}, nil,
+	)
+	overrideDesc = prometheus.NewDesc(
+		"circuit_breaker_override",
+		"Whether the circuit breaker is forced open or closed.",
+		[]string{"name", "override"}, nil,
+	)
+	requestsDesc = prometheus.NewDesc(
+		"circuit_breaker_requests",
+		"Requests seen by each circuit breaker in its current interval.",
+		[]string{"name"}, nil,
+	)
+	failuresDesc = prometheus.NewDesc(
+		"circuit_breaker_failures",
+		"Failed requests seen by each circuit breaker in its current interval.",
+		[]string{"name"}, nil,
+	)
+	consecutiveFailuresDesc = prometheus.NewDesc(
+		"circuit_breaker_consecutive_failures",
+		"Failures in a row seen by each circuit breaker.",
+		[]string{"name"}, nil,
+	)
+)
+
+func init() {
+	prometheus.MustRegister(stateChanges, collector{})
+}
+
+// collector reports the breakers in the registry when metrics are scraped
+type collector struct{}
+
+func (collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateDesc
+	ch <- overrideDesc
+	ch <- requestsDesc
+	ch <- failuresDesc
+	ch <- consecutiveFailuresDesc
+}
+
+func (collector) Collect(ch chan<- prometheus.Metric) {
+	for _, breaker := range Breakers() {
+		name := breaker.Name()
+		counts := breaker.Counts()
+
+		ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, stateValue(breaker.State()), name)
+		for _, override := range []Override{OverrideOpen, OverrideClosed} {
+			value := 0.0
+			if breaker.Override() == override {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(overrideDesc, prometheus.GaugeValue, value, name, override.String())
+		}
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.GaugeValue, float64(counts.Requests), name)
+		ch <- prometheus.MustNewConstMetric(failuresDesc, prometheus.GaugeValue, float64(counts.TotalFailures), name)
+		ch <- prometheus.MustNewConstMetric(consecutiveFailuresDesc, prometheus.GaugeValue, float64(counts.ConsecutiveFailures), name)
+	}
+}
+
+func stateValue(state gobreaker.State) float64 {
+	switch state {
+	case gobreaker.StateHalfOpen:
+		return 1
+	case gobreaker.StateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/domainerr"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const stockCacheKeyPattern = "stock:exists:*"
+
+// Number of keys deleted per round trip when the whole cache is purged
+const stockCachePurgeBatch = 500
+
+// StockCache is a StockClient that remembers which symbols exist
+type StockCache interface {
+	StockClient
+	// Purge forgets one symbol, or every symbol when stock is empty, and returns the number of entries removed
+	Purge(ctx context.Context, stock string) (int64, error)
+}
+
+// StockCacheTTL controls how long existence checks are trusted
+type StockCacheTTL struct {
+	Found    time.Duration
+	NotFound time.Duration
+	// Stale is how long an expired entry is kept to answer while the stock service is unavailable
+	Stale time.Duration
+}
+
+// stockCacheEntry is the outcome of an existence check. Unknown symbols keep
+// the message of the stock service so a cached 404 reads like a live one.
+type stockCacheEntry struct {
+	Found      bool          `json:"found"`
+	Stock      *entity.Stock `json:"stock,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	FreshUntil time.Time     `json:"fresh_until"`
+}
+
+func stockCacheKey(stock string) string {
+	return fmt.Sprintf("stock:exists:%s", stock)
+}
+
+type cachedStockClient struct {
+	next  StockClient
+	cache *cache.Cache
+	ttl   StockCacheTTL
+}
+
+// NewCachedStockClient caches the stock lookups of next in Redis. Quotes and
+// the underwriter catalog are not cached.
+func NewCachedStockClient(next StockClient, cache *cache.Cache, ttl StockCacheTTL) StockCache {
+	return &cachedStockClient{
+		next:  next,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (c *cachedStockClient) GetStock(ctx context.Context, stock string) (*entity.Stock, error) {
+	key := stockCacheKey(stock)
+
+	var entry *stockCacheEntry
+	if cached, ok := c.cache.Get(ctx, key); ok {
+		entry = &stockCacheEntry{}
+		if err := json.Unmarshal([]byte(cached), entry); err != nil {
+			entry = nil
+		}
+	}
+
+	// Entries written before listings were cached only know that the stock exists, they are refreshed
+	if entry != nil && time.Now().Before(entry.FreshUntil) && (!entry.Found || entry.Stock != nil) {
+		c.cache.RecordLookup("stock", true)
+		return entry.result(stock)
+	}
+	c.cache.RecordLookup("stock", false)
+
+	result, err := c.next.GetStock(ctx, stock)
+
+	var serviceErr *domainerr.ServiceError
+	switch {
+	case err == nil:
+		c.store(ctx, key, stockCacheEntry{Found: true, Stock: result}, c.ttl.Found)
+
+	case errors.As(err, &serviceErr) && serviceErr.Code == http.StatusNotFound:
+		c.store(ctx, key, stockCacheEntry{Message: serviceErr.Message}, c.ttl.NotFound)
+
+	case entry != nil && (errors.Is(err, domainerr.ErrStockServiceUnavailable) || errors.Is(err, domainerr.ErrServiceTimeout)):
+		logging.Warn(ctx, "serving stale existence check", "stock", stock, "error", err)
+		return entry.result(stock)
+	}
+	return result, err
+}
+
+func (c *cachedStockClient) GetQuotes(ctx context.Context, stocks []string) []QuoteResult {
+	return c.next.GetQuotes(ctx, stocks)
+}
+
+func (c *cachedStockClient) GetUnderwriters(ctx context.Context) ([]entity.Underwriter, error) {
+	return c.next.GetUnderwriters(ctx)
+}
+
+func (c *cachedStockClient) SearchUnderwriters(ctx context.Context, query string) ([]entity.Underwriter, error) {
+	return c.next.SearchUnderwriters(ctx, query)
+}
+
+func (c *cachedStockClient) Purge(ctx context.Context, stock string) (int64, error) {
+	var purged int64
+	err := c.cache.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		if stock != "" {
+			var err error
+			purged, err = client.Del(ctx, stockCacheKey(stock)).Result()
+			return err
+		}
+
+		iter := client.Scan(ctx, 0, stockCacheKeyPattern, stockCachePurgeBatch).Iterator()
+		keys := make([]string, 0, stockCachePurgeBatch)
+		for {
+			more := iter.Next(ctx)
+			if more {
+				keys = append(keys, iter.Val())
+			}
+
+			if len(keys) == stockCachePurgeBatch || (!more && len(keys) > 0) {
+				deleted, err := client.Del(ctx, keys...).Result()
+				if err != nil {
+					return err
+				}
+				purged += deleted
+				keys = keys[:0]
+			}
+
+			if !more {
+				return iter.Err()
+			}
+		}
+	})
+	if err != nil {
+		return purged, domainerr.ErrCacheUnavailable
+	}
+	return purged, nil
+}
+
+// store keeps an entry for its fresh period plus the stale period
+func (c *cachedStockClient) store(ctx context.Context, key string, entry stockCacheEntry, ttl time.Duration) {
+	entry.FreshUntil = time.Now().Add(ttl)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	c.cache.Set(ctx, key, data, ttl+c.ttl.Stale)
+}
+
+func (entry *stockCacheEntry) result(stock string) (*entity.Stock, error) {
+	if entry.Stock != nil {
+		return entry.Stock, nil
+	}
+
+	if entry.Found {
+		return &entity.Stock{Symbol: stock}, nil
+	}
+	return nil, domainerr.NewServiceError(http.StatusNotFound, entry.Message)
+}
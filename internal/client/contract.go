@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The structs below are the contract of the Stock Backend. Responses are
+// decoded strictly: unknown fields, trailing data and missing required fields
+// are errors, so a change on the other side fails loudly instead of zeroing values.
+
+// webResponse is the envelope of every Stock Backend response
+type webResponse[T any] struct {
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+type stockData struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Sector string `json:"sector"`
+}
+
+func (data stockData) validate() error {
+	if data.Code == "" || data.Name == "" {
+		return errors.New("stock without code or name")
+	}
+	return nil
+}
+
+type quoteData struct {
+	Code          string  `json:"code"`
+	LastPrice     float64 `json:"last_price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"`
+}
+
+type quotesData []quoteData
+
+func (data quotesData) validate() error {
+	for _, quote := range data {
+		if quote.Code == "" {
+			return errors.New("quote without code")
+		}
+	}
+	return nil
+}
+
+type underwriterData struct {
+	Code     string         `json:"code"`
+	Name     string         `json:"name"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+type underwritersData []underwriterData
+
+func (data underwritersData) validate() error {
+	for _, underwriter := range data {
+		if underwriter.Code == "" {
+			return errors.New("underwriter without code")
+		}
+	}
+	return nil
+}
+
+type validator interface {
+	validate() error
+}
+
+// decodeStrict decodes a single JSON document into v and validates it
+func decodeStrict(body io.Reader, v any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("decode stock service response: %w", err)
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("decode stock service response: unexpected data after the response")
+	}
+
+	if checked, ok := v.(validator); ok {
+		if err := checked.validate(); err != nil {
+			return fmt.Errorf("decode stock service response: %w", err)
+		}
+	}
+	return nil
+}
+
+// validate checks the data of a successful response
+func (response *webResponse[T]) validate() error {
+	if checked, ok := any(response.Data).(validator); ok {
+		return checked.validate()
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package fakestock is an in-memory Stock Backend for tests. It serves the
+// endpoints StockClient depends on and can be scripted to answer slowly or fail.
+package fakestock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"stock_backend/internal/client"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StockPath              = "/api/v1/stocks"
+	QuotesPath             = "/api/v1/stocks/quotes"
+	UnderwritersPath       = "/api/v1/underwriters"
+	SearchUnderwritersPath = "/api/v1/underwriters/search"
+)
+
+type Stock struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Sector string `json:"sector"`
+}
+
+type Quote struct {
+	Code          string  `json:"code"`
+	LastPrice     float64 `json:"last_price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"`
+}
+
+type Underwriter struct {
+	Code     string         `json:"code"`
+	Name     string         `json:"name"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Step scripts one answer of an endpoint. The request waits for Latency, then
+// fails with Status, or is served normally when Status is 0. A Body replaces
+// the normal answer, which is how malformed responses are simulated.
+type Step struct {
+	Latency time.Duration
+	Status  int
+	Body    string
+}
+
+// Request is a request the server received
+type Request struct {
+	Path        string
+	Query       url.Values
+	RequestID   string
+	TraceParent string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	stocks       map[string]Stock
+	quotes       map[string]Quote
+	underwriters []Underwriter
+	latency      time.Duration
+	scripts      map[string][]Step
+	requests     []Request
+}
+
+// New starts a server. Close it when the test is done.
+func New() *Server {
+	server := &Server{
+		stocks:  map[string]Stock{},
+		quotes:  map[string]Quote{},
+		scripts: map[string][]Step{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(StockPath, server.handle(server.getStock))
+	mux.HandleFunc(QuotesPath, server.handle(server.getQuotes))
+	mux.HandleFunc(UnderwritersPath, server.handle(server.getUnderwriters))
+	mux.HandleFunc(SearchUnderwritersPath, server.handle(server.searchUnderwriters))
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// Routes sends every exchange to this server
+func (server *Server) Routes() client.StockRoutes {
+	return client.StockRoutes{
+		"IDX":    server.URL,
+		"NASDAQ": server.URL,
+		"NYSE":   server.URL,
+	}
+}
+
+// AddStocks lists stocks. Their codes are matched without the exchange.
+func (server *Server) AddStocks(stocks ...Stock) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, stock := range stocks {
+		server.stocks[strings.ToUpper(stock.Code)] = stock
+	}
+}
+
+// SetQuotes sets the quotes served for listed stocks
+func (server *Server) SetQuotes(quotes ...Quote) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, quote := range quotes {
+		server.quotes[strings.ToUpper(quote.Code)] = quote
+	}
+}
+
+func (server *Server) AddUnderwriters(underwriters ...Underwriter) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.underwriters = append(server.underwriters, underwriters...)
+}
+
+// SetLatency delays every answer that is not scripted
+func (server *Server) SetLatency(latency time.Duration) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.latency = latency
+}
+
+// Script queues answers for the next requests to path. Once the steps are used
+// up the endpoint answers normally again.
+func (server *Server) Script(path string, steps ...Step) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.scripts[path] = append(server.scripts[path], steps...)
+}
+
+// Requests returns the requests received on path, in order
+func (server *Server) Requests(path string) []Request {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	requests := []Request{}
+	for _, request := range server.requests {
+		if request.Path == path {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// Reset forgets scripted steps, the latency and the received requests, keeping the data
+func (server *Server) Reset() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.scripts = map[string][]Step{}
+	server.latency = 0
+	server.requests = nil
+}
+
+func (server *Server) handle(serve func(query url.Values) (int, any)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		step := server.next(r)
+
+		if step.Latency > 0 {
+			select {
+			case <-time.After(step.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if step.Body != "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(max(step.Status, http.StatusOK))
+			_, _ = w.Write([]byte(step.Body))
+			return
+		}
+
+		if step.Status != 0 {
+			w.WriteHeader(step.Status)
+			return
+		}
+
+		status, body := serve(r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// next records the request and returns how to answer it
+func (server *Server) next(r *http.Request) Step {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.requests = append(server.requests, Request{
+		Path:        r.URL.Path,
+		Query:       r.URL.Query(),
+		RequestID:   r.Header.Get("X-Request-ID"),
+		TraceParent: r.Header.Get("traceparent"),
+	})
+
+	steps := server.scripts[r.URL.Path]
+	if len(steps) == 0 {
+		return Step{Latency: server.latency}
+	}
+	server.scripts[r.URL.Path] = steps[1:]
+	return steps[0]
+}
+
+type webResponse struct {
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (server *Server) getStock(query url.Values) (int, any) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	stock, ok := server.stocks[strings.ToUpper(query.Get("code"))]
+	if !ok {
+		return http.StatusNotFound, webResponse{Message: "Stock not found"}
+	}
+	return http.StatusOK, webResponse{Message: "Stock found", Data: stock}
+}
+
+func (server *Server) getQuotes(query url.Values) (int, any) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	quotes := []Quote{}
+	for _, code := range strings.Split(query.Get("codes"), ",") {
+		if quote, ok := server.quotes[strings.ToUpper(code)]; ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	return http.StatusOK, webResponse{Message: "Quotes found", Data: quotes}
+}
+
+func (server *Server) getUnderwriters(query url.Values) (int, any) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return http.StatusOK, webResponse{Message: "Underwriters found", Data: server.sortedUnderwriters("")}
+}
+
+func (server *Server) searchUnderwriters(query url.Values) (int, any) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return http.StatusOK, webResponse{Message: "Underwriters found", Data: server.sortedUnderwriters(query.Get("q"))}
+}
+
+func (server *Server) sortedUnderwriters(search string) []Underwriter {
+	search = strings.ToLower(strings.TrimSpace(search))
+	underwriters := []Underwriter{}
+	for _, underwriter := range server.underwriters {
+		if strings.Contains(strings.ToLower(underwriter.Code), search) || strings.Contains(strings.ToLower(underwriter.Name), search) {
+			underwriters = append(underwriters, underwriter)
+		}
+	}
+
+	sort.Slice(underwriters, func(i, j int) bool {
+		return underwriters[i].Code < underwriters[j].Code
+	})
+	return underwriters
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"stock_backend/internal/model/domainerr"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+)
+
+var (
+	stockRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stock_client_requests_total",
+		Help: "Calls to the Stock Backend by endpoint and outcome, counting retries of a call once.",
+	}, []string{"endpoint", "outcome"})
+
+	stockRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "stock_client_request_duration_seconds",
+		Help:    "Latency of calls to the Stock Backend by endpoint, including retries and backoff.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	stockRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stock_client_retries_total",
+		Help: "Attempts beyond the first made to the Stock Backend by endpoint.",
+	}, []string{"endpoint"})
+)
+
+func init() {
+	prometheus.MustRegister(stockRequests, stockRequestDuration, stockRetries)
+}
+
+func observeRequest(endpoint string, start time.Time, attempts int, err error) {
+	stockRequests.WithLabelValues(endpoint, requestOutcome(err)).Inc()
+	stockRequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	if attempts > 1 {
+		stockRetries.WithLabelValues(endpoint).Add(float64(attempts - 1))
+	}
+}
+
+// requestOutcome labels the error of a call before it is mapped for the caller
+func requestOutcome(err error) string {
+	var serviceErr *domainerr.ServiceError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return "success"
+
+	case errors.Is(err, gobreaker.ErrOpenState),
+		errors.Is(err, gobreaker.ErrTooManyRequests):
+		return "circuit_open"
+
+	case errors.As(err, &serviceErr):
+		if serviceErr.Code < http.StatusInternalServerError {
+			return "client_error"
+		}
+		return "server_error"
+
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+
+	default:
+		return "error"
+	}
+}
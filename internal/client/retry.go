@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/domainerr"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// retryPolicy retries transient failures with exponential backoff and full jitter
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: 3,
+	baseDelay:   100 * time.Millisecond,
+	maxDelay:    time.Second,
+}
+
+// do calls fn until it succeeds, fails permanently or runs out of attempts.
+// It gives up early when the next attempt could not start before the deadline of ctx.
+func (policy retryPolicy) do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < policy.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := policy.backoff(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+				return err
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		err = fn()
+		if err == nil || !isTransient(ctx, err) {
+			return err
+		}
+	}
+	return err
+}
+
+// backoff returns a random delay up to baseDelay * 2^(attempt-1), capped at maxDelay
+func (policy retryPolicy) backoff(attempt int) time.Duration {
+	ceiling := policy.maxDelay
+	if shift := attempt - 1; shift < 30 && policy.baseDelay<<shift < ceiling {
+		ceiling = policy.baseDelay << shift
+	}
+	return rand.N(ceiling) + 1
+}
+
+// isTransient reports whether another attempt may succeed. Network errors and
+// gateway errors are transient, while 4xx answers and the caller giving up are not.
+func isTransient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var serviceErr *domainerr.ServiceError
+	if errors.As(err, &serviceErr) {
+		switch serviceErr.Code {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// mapClientError classifies the final outcome of a call to the stock service.
+// A 4xx answer is returned as is so its message can reach the user.
+func mapClientError(ctx context.Context, err error) error {
+	var serviceErr *domainerr.ServiceError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+
+	case errors.Is(err, gobreaker.ErrOpenState),
+		errors.Is(err, gobreaker.ErrTooManyRequests):
+		return domainerr.ErrStockServiceUnavailable
+
+	case errors.As(err, &serviceErr):
+		if serviceErr.Code < http.StatusInternalServerError {
+			return serviceErr
+		}
+		logging.Error(ctx, "stock service error", "error", err)
+		return domainerr.ErrStockServiceUnavailable
+
+	case errors.Is(err, context.Canceled):
+		return err
+
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return domainerr.ErrServiceTimeout
+
+	default:
+		logging.Error(ctx, "stock service error", "error", err)
+		return domainerr.ErrStockServiceUnavailable
+	}
+}
@@ -3,83 +3,337 @@ package client
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"net"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"stock_backend/config"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/helper"
 	"stock_backend/internal/model/domainerr"
-	"stock_backend/internal/model/response"
+	"stock_backend/internal/tracing"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/sony/gobreaker"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	// Maximum number of batch quote requests in flight for a single GetQuotes call
+	maxConcurrentQuotes = 5
+	// Maximum number of stocks asked for in one batch quote request
+	maxQuotesPerRequest = 50
+)
+
+// StockServiceBreaker names the circuit breaker shared by every caller of the Stock Backend
+const StockServiceBreaker = "stock-service"
+
+// BreakerName names the breaker guarding the service of an exchange. The IDX
+// service keeps the plain StockServiceBreaker name.
+func BreakerName(exchange string) string {
+	if exchange == entity.DefaultExchange {
+		return StockServiceBreaker
+	}
+	return StockServiceBreaker + "-" + strings.ToLower(exchange)
+}
+
+// StockClient covers the Stock Backend endpoints this service depends on
 type StockClient interface {
-	GetStock(ctx context.Context, ticker string) error
+	GetStock(ctx context.Context, stock string) (*entity.Stock, error)
+	GetQuotes(ctx context.Context, stocks []string) []QuoteResult
+	GetUnderwriters(ctx context.Context) ([]entity.Underwriter, error)
+	SearchUnderwriters(ctx context.Context, query string) ([]entity.Underwriter, error)
+}
+
+// QuoteResult holds the quote of a single stock or the reason it is unavailable
+type QuoteResult struct {
+	Stock string
+	Quote *entity.Quote
+	Err   error
+}
+
+// StockRoutes maps an exchange to the base URL of the service that lists it
+type StockRoutes map[string]string
+
+// NewStockRoutes routes IDX to the URL of cfg and the other exchanges to their service in URLs
+func NewStockRoutes(cfg config.StockService) StockRoutes {
+	routes := StockRoutes{entity.DefaultExchange: cfg.URL}
+	for exchange, url := range cfg.URLs {
+		routes[strings.ToUpper(exchange)] = url
+	}
+	return routes
+}
+
+// Breaker guards the calls to the stock service
+type Breaker interface {
+	Execute(req func() (interface{}, error)) (interface{}, error)
 }
 
 type stockClient struct {
 	httpClient *http.Client
-	breaker    *gobreaker.CircuitBreaker
-	baseUrl    string
+	// breaker of each base URL, so an outage of one exchange service does not cut off the others
+	breakers map[string]Breaker
+	routes   StockRoutes
+	retry    retryPolicy
 }
 
-func NewStockClient(url string, breaker *gobreaker.CircuitBreaker) StockClient {
+// NewStockClient calls newBreaker once per service in routes. Exchanges routed
+// to the same base URL share the breaker, named after IDX when it is one of them.
+func NewStockClient(routes StockRoutes, newBreaker func(name string) Breaker) StockClient {
+	// IDX goes first so a service it shares keeps the plain name
+	exchanges := append([]string{entity.DefaultExchange}, slices.Sorted(maps.Keys(routes))...)
+
+	breakers := map[string]Breaker{}
+	for _, exchange := range exchanges {
+		baseUrl, ok := routes[exchange]
+		if !ok {
+			continue
+		}
+		if _, exists := breakers[baseUrl]; !exists {
+			breakers[baseUrl] = newBreaker(BreakerName(exchange))
+		}
+	}
+
 	return &stockClient{
-		httpClient: &http.Client{Timeout: 5 * time.Second},
-		breaker:    breaker,
-		baseUrl:    url,
+		// The transport opens a span per attempt and sends its traceparent
+		httpClient: &http.Client{Timeout: 5 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		breakers:   breakers,
+		routes:     routes,
+		retry:      defaultRetryPolicy,
 	}
 }
 
-func (c *stockClient) GetStock(ctx context.Context, stock string) error {
-	_, err := c.breaker.Execute(func() (interface{}, error) {
-		url := fmt.Sprintf("%s/api/v1/stocks?code=%s", c.baseUrl, stock)
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// resolve returns the code of the symbol and the base URL of the service for its exchange
+func (c *stockClient) resolve(stock string) (string, string, error) {
+	symbol, ok := entity.ParseSymbol(stock)
+	if !ok {
+		return "", "", domainerr.ErrInvalidSymbol
+	}
+
+	baseUrl := c.routes[symbol.Exchange]
+	if baseUrl == "" {
+		return "", "", domainerr.ErrUnsupportedExchange
+	}
+	return symbol.Code, baseUrl, nil
+}
+
+// GetStock returns the listing of a stock. An unknown stock is a *domainerr.ServiceError with code 404.
+func (c *stockClient) GetStock(ctx context.Context, stock string) (*entity.Stock, error) {
+	ctx, span := tracing.Start(ctx, "StockClient.GetStock")
+	defer span.End()
+
+	code, baseUrl, err := c.resolve(stock)
+	if err != nil {
+		return nil, err
+	}
+
+	var res webResponse[stockData]
+	url := fmt.Sprintf("%s/api/v1/stocks?code=%s", baseUrl, url.QueryEscape(code))
+	if err := c.fetch(ctx, "stock", baseUrl, url, &res); err != nil {
+		return nil, err
+	}
+
+	return &entity.Stock{
+		Symbol: stock,
+		Name:   res.Data.Name,
+		Sector: res.Data.Sector,
+	}, nil
+}
+
+// quoteBatch is one batch quote request to the service of an exchange
+type quoteBatch struct {
+	baseUrl string
+	codes   []string
+	// indexes of each code in the results, a code is requested once even when
+	// several stocks resolve to it
+	indexes map[string][]int
+}
+
+// GetQuotes fetches the quotes of the stocks in batches per exchange service.
+// A failed quote is reported in its own QuoteResult so one stock never fails
+// the whole call.
+func (c *stockClient) GetQuotes(ctx context.Context, stocks []string) []QuoteResult {
+	ctx, span := tracing.Start(ctx, "StockClient.GetQuotes")
+	defer span.End()
+
+	results := make([]QuoteResult, len(stocks))
+	batches := []*quoteBatch{}
+	open := map[string]*quoteBatch{}
+	// batch already holding each code, keyed by base url and code
+	queued := map[[2]string]*quoteBatch{}
+	for i, stock := range stocks {
+		results[i] = QuoteResult{Stock: stock, Err: domainerr.ErrQuoteUnavailable}
+
+		code, baseUrl, err := c.resolve(stock)
 		if err != nil {
-			return nil, err
+			results[i].Err = err
+			continue
 		}
 
-		// Call the request
-		res, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
+		key := [2]string{baseUrl, code}
+		if batch := queued[key]; batch != nil {
+			batch.indexes[code] = append(batch.indexes[code], i)
+			continue
 		}
-		defer res.Body.Close()
 
-		// If the response is not ok
-		if res.StatusCode != http.StatusOK {
-			var webResponse response.WebResponse
-			if err := json.NewDecoder(res.Body).Decode(&webResponse); err != nil {
-				return nil, err
+		batch := open[baseUrl]
+		if batch == nil || len(batch.codes) == maxQuotesPerRequest {
+			batch = &quoteBatch{baseUrl: baseUrl, indexes: map[string][]int{}}
+			open[baseUrl] = batch
+			batches = append(batches, batch)
+		}
+		batch.codes = append(batch.codes, code)
+		batch.indexes[code] = []int{i}
+		queued[key] = batch
+	}
+
+	sem := make(chan struct{}, maxConcurrentQuotes)
+	var wg sync.WaitGroup
+	for _, batch := range batches {
+		wg.Add(1)
+		go func(batch *quoteBatch) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				batch.fail(results, ctx.Err())
+				return
 			}
 
-			err := domainerr.NewServiceError(res.StatusCode, webResponse.Message)
-			return nil, err
-		}
-		return nil, nil
-	})
+			var res webResponse[quotesData]
+			url := fmt.Sprintf("%s/api/v1/stocks/quotes?codes=%s", batch.baseUrl, url.QueryEscape(strings.Join(batch.codes, ",")))
+			if err := c.fetch(ctx, "quotes", batch.baseUrl, url, &res); err != nil {
+				batch.fail(results, err)
+				return
+			}
 
-	if err != nil {
-		if errors.Is(err, gobreaker.ErrOpenState) {
-			return domainerr.ErrStockServiceUnavailable
+			// Stocks missing from the answer keep ErrQuoteUnavailable
+			for _, quote := range res.Data {
+				for _, i := range batch.indexes[strings.ToUpper(quote.Code)] {
+					results[i] = QuoteResult{
+						Stock: stocks[i],
+						Quote: &entity.Quote{
+							Stock:         stocks[i],
+							LastPrice:     quote.LastPrice,
+							Change:        quote.Change,
+							ChangePercent: quote.ChangePercent,
+							Volume:        quote.Volume,
+						},
+					}
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (batch *quoteBatch) fail(results []QuoteResult, err error) {
+	for _, indexes := range batch.indexes {
+		for _, i := range indexes {
+			results[i].Err = err
 		}
+	}
+}
 
-		return err
+// GetUnderwriters fetches the underwriter catalog maintained by the stock service
+func (c *stockClient) GetUnderwriters(ctx context.Context) ([]entity.Underwriter, error) {
+	ctx, span := tracing.Start(ctx, "StockClient.GetUnderwriters")
+	defer span.End()
+
+	// Underwriters are IDX brokers
+	baseUrl := c.routes[entity.DefaultExchange]
+	url := fmt.Sprintf("%s/api/v1/underwriters", baseUrl)
+	return c.fetchUnderwriters(ctx, "underwriters", baseUrl, url)
+}
+
+// SearchUnderwriters finds underwriters whose code or name matches query
+func (c *stockClient) SearchUnderwriters(ctx context.Context, query string) ([]entity.Underwriter, error) {
+	ctx, span := tracing.Start(ctx, "StockClient.SearchUnderwriters")
+	defer span.End()
+
+	baseUrl := c.routes[entity.DefaultExchange]
+	url := fmt.Sprintf("%s/api/v1/underwriters/search?q=%s", baseUrl, url.QueryEscape(query))
+	return c.fetchUnderwriters(ctx, "search_underwriters", baseUrl, url)
+}
+
+func (c *stockClient) fetchUnderwriters(ctx context.Context, endpoint string, baseUrl string, url string) ([]entity.Underwriter, error) {
+	var res webResponse[underwritersData]
+	if err := c.fetch(ctx, endpoint, baseUrl, url, &res); err != nil {
+		return nil, err
 	}
 
-	return nil
+	underwriters := make([]entity.Underwriter, 0, len(res.Data))
+	for _, underwriter := range res.Data {
+		underwriters = append(underwriters, entity.Underwriter{
+			ID:       strings.ToUpper(underwriter.Code),
+			Name:     underwriter.Name,
+			Metadata: underwriter.Metadata,
+		})
+	}
+	return underwriters, nil
+}
+
+// fetch GETs url and strictly decodes a successful response into res.
+// Transient failures are retried inside one call of the breaker of baseUrl, so
+// the breaker only counts the final outcome of each request. endpoint names
+// the call in the metrics.
+func (c *stockClient) fetch(ctx context.Context, endpoint string, baseUrl string, url string, res validator) error {
+	// Every attempt carries the same ID so the stock service can tie retries together
+	requestId := helper.GetRequestID(ctx)
+	if requestId == "" {
+		requestId = uuid.NewString()
+	}
+
+	start := time.Now()
+	attempts := 0
+	_, err := c.breakers[baseUrl].Execute(func() (interface{}, error) {
+		return nil, c.retry.do(ctx, func() error {
+			attempts++
+			return c.get(ctx, url, requestId, res)
+		})
+	})
+	observeRequest(endpoint, start, attempts, err)
+	if err != nil {
+		span := trace.SpanFromContext(ctx)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, requestOutcome(err))
+	}
+	return mapClientError(ctx, err)
 }
 
-func mapClientError(err error) error {
-	var netErr *net.OpError
-	if errors.As(err, &netErr) {
-		return domainerr.ErrStockServiceUnavailable
+func (c *stockClient) get(ctx context.Context, url string, requestId string, res validator) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
 	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(helper.RequestIDHeader, requestId)
 
-	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout") {
-		return domainerr.ErrServiceTimeout
+	// Call the request
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
-	return domainerr.ErrStockServiceUnavailable
+	defer httpRes.Body.Close()
+
+	// If the response is not ok. Proxies answer gateway errors without our JSON body.
+	if httpRes.StatusCode != http.StatusOK {
+		var failed webResponse[json.RawMessage]
+		message := http.StatusText(httpRes.StatusCode)
+		if err := json.NewDecoder(httpRes.Body).Decode(&failed); err == nil && failed.Message != "" {
+			message = failed.Message
+		}
+		return domainerr.NewServiceError(httpRes.StatusCode, message)
+	}
+
+	return decodeStrict(httpRes.Body, res)
 }
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/service"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AlertHandler interface {
+	GetAlerts(c *fiber.Ctx) error
+	CreateAlert(c *fiber.Ctx) error
+	UpdateAlert(c *fiber.Ctx) error
+	DeleteAlert(c *fiber.Ctx) error
+	GetAlertEvents(c *fiber.Ctx) error
+}
+
+type AlertHandlerImpl struct {
+	Service   service.AlertService
+	Validator *validator.Validate
+}
+
+func NewAlertHandler(service service.AlertService, validator *validator.Validate) AlertHandler {
+	return &AlertHandlerImpl{
+		Service:   service,
+		Validator: validator,
+	}
+}
+
+func (handler *AlertHandlerImpl) GetAlerts(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.GetAlerts(ctx, userId)
+	if err != nil {
+		status, message := MapAlertErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *AlertHandlerImpl) CreateAlert(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.CreateAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.CreateAlert(ctx, userId, req)
+	if err != nil {
+		status, message := MapAlertErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (handler *AlertHandlerImpl) UpdateAlert(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	alertId := c.Params("id")
+	if _, err := uuid.Parse(alertId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrAlertIdInvalid.Error())
+	}
+
+	var req request.UpdateAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.UpdateAlert(ctx, userId, alertId, req)
+	if err != nil {
+		status, message := MapAlertErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *AlertHandlerImpl) DeleteAlert(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	alertId := c.Params("id")
+	if _, err := uuid.Parse(alertId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrAlertIdInvalid.Error())
+	}
+
+	res, err := handler.Service.DeleteAlert(ctx, userId, alertId)
+	if err != nil {
+		status, message := MapAlertErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *AlertHandlerImpl) GetAlertEvents(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	alertId := c.Params("id")
+	if _, err := uuid.Parse(alertId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrAlertIdInvalid.Error())
+	}
+
+	res, err := handler.Service.GetAlertEvents(ctx, userId, alertId)
+	if err != nil {
+		status, message := MapAlertErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CacheHandler interface {
+	GetCacheStatus(c *fiber.Ctx) error
+	PurgeStockCache(c *fiber.Ctx) error
+}
+
+type CacheHandlerImpl struct {
+	Cache      *cache.Cache
+	StockCache client.StockCache
+}
+
+func NewCacheHandler(cache *cache.Cache, stockCache client.StockCache) CacheHandler {
+	return &CacheHandlerImpl{
+		Cache:      cache,
+		StockCache: stockCache,
+	}
+}
+
+// GetCacheStatus reports whether the service is running without its Redis cache
+func (handler *CacheHandlerImpl) GetCacheStatus(c *fiber.Ctx) error {
+	res := &response.GetCacheStatusResponse{
+		Message: "Cache status retrieved successfully",
+		Data: response.CacheStatus{
+			Degraded:             handler.Cache.Degraded(),
+			PendingInvalidations: handler.Cache.PendingInvalidations(),
+		},
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// PurgeStockCache forgets the cached existence check of one stock, or of every stock when none is given
+func (handler *CacheHandlerImpl) PurgeStockCache(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	stock := ""
+	if raw := c.Query("stock"); raw != "" {
+		symbol, err := helper.NormalizeSymbol(raw)
+		if err != nil {
+			status, message := MapCacheErrorToHTTPStatus(c.UserContext(), err)
+			return ResponseErrorJSON(c, status, message)
+		}
+		stock = symbol.String()
+	}
+
+	purged, err := handler.StockCache.Purge(ctx, stock)
+	if err != nil {
+		status, message := MapCacheErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	res := &response.PurgeStockCacheResponse{
+		Message: "Stock cache purged successfully",
+		Data: response.PurgedStockCache{
+			Purged: purged,
+		},
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"stock_backend/internal/circuit"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CircuitHandler interface {
+	GetCircuitBreakers(c *fiber.Ctx) error
+	SetCircuitOverride(c *fiber.Ctx) error
+}
+
+type CircuitHandlerImpl struct{}
+
+func NewCircuitHandler() CircuitHandler {
+	return &CircuitHandlerImpl{}
+}
+
+func (handler *CircuitHandlerImpl) GetCircuitBreakers(c *fiber.Ctx) error {
+	breakers := circuit.Breakers()
+	data := make([]response.CircuitBreaker, 0, len(breakers))
+	for _, breaker := range breakers {
+		data = append(data, newCircuitBreakerResponse(breaker))
+	}
+
+	res := &response.GetCircuitBreakersResponse{
+		Message: "Circuit breakers retrieved successfully",
+		Data:    data,
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// SetCircuitOverride forces a breaker open or closed during an incident, or hands it back to its settings
+func (handler *CircuitHandlerImpl) SetCircuitOverride(c *fiber.Ctx) error {
+	var req request.SetCircuitOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	override, err := circuit.ParseOverride(req.Override)
+	if err != nil {
+		status, message := MapCircuitErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	breaker, err := circuit.GetBreaker(c.Params("name"))
+	if err != nil {
+		status, message := MapCircuitErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+	breaker.SetOverride(override)
+
+	res := &response.SetCircuitOverrideResponse{
+		Message: "Circuit breaker override updated successfully",
+		Data:    newCircuitBreakerResponse(breaker),
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func newCircuitBreakerResponse(breaker *circuit.Breaker) response.CircuitBreaker {
+	counts := breaker.Counts()
+	settings := breaker.Settings()
+	return response.CircuitBreaker{
+		Name:     breaker.Name(),
+		State:    breaker.State().String(),
+		Override: breaker.Override().String(),
+		Counts: response.CircuitCounts{
+			Requests:             counts.Requests,
+			TotalSuccesses:       counts.TotalSuccesses,
+			TotalFailures:        counts.TotalFailures,
+			ConsecutiveSuccesses: counts.ConsecutiveSuccesses,
+			ConsecutiveFailures:  counts.ConsecutiveFailures,
+		},
+		Settings: response.CircuitSettings{
+			MaxRequests:  settings.MaxRequests,
+			Interval:     settings.Interval.String(),
+			Timeout:      settings.Timeout.String(),
+			MinRequests:  settings.MinRequests,
+			FailureRatio: settings.FailureRatio,
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"context"
+	"stock_backend/internal/logging"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestContext bounds the work of a handler to timeout and adds the route to
+// the logger of the request, so every layer below logs which endpoint it served
+func requestContext(c *fiber.Ctx, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c.SetUserContext(logging.With(c.UserContext(), "route", c.Route().Path))
+	return context.WithTimeout(c.UserContext(), timeout)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailOutboxHandler interface {
+	GetEmails(c *fiber.Ctx) error
+	RetryEmail(c *fiber.Ctx) error
+}
+
+type EmailOutboxHandlerImpl struct {
+	Service service.EmailOutboxService
+}
+
+func NewEmailOutboxHandler(service service.EmailOutboxService) EmailOutboxHandler {
+	return &EmailOutboxHandlerImpl{
+		Service: service,
+	}
+}
+
+func (handler *EmailOutboxHandlerImpl) GetEmails(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.GetEmails(ctx, c.Query("status"))
+	if err != nil {
+		status, message := MapEmailErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *EmailOutboxHandlerImpl) RetryEmail(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	emailId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || emailId <= 0 {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrEmailIdInvalid.Error())
+	}
+
+	res, err := handler.Service.RetryEmail(ctx, emailId)
+	if err != nil {
+		status, message := MapEmailErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -3,7 +3,7 @@ package handler
 import (
 	"context"
 	"errors"
-	"log"
+	"stock_backend/internal/logging"
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/response"
 
@@ -16,7 +16,7 @@ func ResponseErrorJSON(c *fiber.Ctx, code int, message string) error {
 	})
 }
 
-func MapErrorToHTTPStatus(err error) (int, string) {
+func MapErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
 	switch {
 	case errors.Is(err, domainerr.ErrUserNotFound):
 		return fiber.StatusNotFound, err.Error()
@@ -38,12 +38,12 @@ func MapErrorToHTTPStatus(err error) (int, string) {
 		return fiber.StatusGatewayTimeout, err.Error()
 
 	default:
-		log.Printf("[ERROR] error: %v", err)
+		logging.Error(ctx, "unexpected error", "error", err)
 		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
 	}
 }
 
-func MapFavoritesErrorToHTTPStatus(err error) (int, string) {
+func MapFavoritesErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
 	switch {
 	case errors.Is(err, domainerr.ErrFavoritesNotFound):
 		return fiber.StatusNotFound, err.Error()
@@ -51,16 +51,27 @@ func MapFavoritesErrorToHTTPStatus(err error) (int, string) {
 	case errors.Is(err, domainerr.ErrFavoritesDuplicate):
 		return fiber.StatusConflict, err.Error()
 
+	case errors.Is(err, domainerr.ErrImportEmpty),
+		errors.Is(err, domainerr.ErrImportInvalidFormat),
+		errors.Is(err, domainerr.ErrInvalidRequestBody):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, domainerr.ErrImportTooLarge):
+		return fiber.StatusRequestEntityTooLarge, err.Error()
+
+	case errors.Is(err, domainerr.ErrUnderwriterNotFound):
+		return fiber.StatusUnprocessableEntity, err.Error()
+
 	case errors.Is(err, context.DeadlineExceeded):
 		return fiber.StatusGatewayTimeout, err.Error()
 
 	default:
-		log.Printf("[ERROR] error: %v", err)
+		logging.Error(ctx, "unexpected error", "error", err)
 		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
 	}
 }
 
-func MapWatchlistErrorToHTTPStatus(err error) (int, string) {
+func MapWatchlistErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
 	switch {
 	case errors.Is(err, domainerr.ErrWatchlistNotFound):
 		return fiber.StatusNotFound, err.Error()
@@ -68,6 +79,23 @@ func MapWatchlistErrorToHTTPStatus(err error) (int, string) {
 	case errors.Is(err, domainerr.ErrWatchlistDuplicate):
 		return fiber.StatusConflict, err.Error()
 
+	case errors.Is(err, domainerr.ErrWatchlistSortInvalid),
+		errors.Is(err, domainerr.ErrWatchlistTargetNegative),
+		errors.Is(err, domainerr.ErrInvalidSymbol),
+		errors.Is(err, domainerr.ErrUnsupportedExchange):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, domainerr.ErrWatchlistTargetInvalid):
+		return fiber.StatusUnprocessableEntity, err.Error()
+
+	case errors.Is(err, domainerr.ErrImportEmpty),
+		errors.Is(err, domainerr.ErrImportInvalidFormat),
+		errors.Is(err, domainerr.ErrInvalidRequestBody):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, domainerr.ErrImportTooLarge):
+		return fiber.StatusRequestEntityTooLarge, err.Error()
+
 	case errors.Is(err, domainerr.ErrStockServiceUnavailable):
 		return fiber.StatusServiceUnavailable, err.Error()
 
@@ -76,7 +104,168 @@ func MapWatchlistErrorToHTTPStatus(err error) (int, string) {
 		return fiber.StatusGatewayTimeout, domainerr.ErrServiceTimeout.Error()
 
 	default:
-		log.Printf("[ERROR] error: %v", err)
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapWatchlistShareErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrWatchlistShareNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrWatchlistShareExpiryInvalid):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapAlertErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrAlertNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrAlertStockNotInWatchlist):
+		return fiber.StatusUnprocessableEntity, err.Error()
+
+	case errors.Is(err, domainerr.ErrInvalidSymbol),
+		errors.Is(err, domainerr.ErrUnsupportedExchange):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapPortfolioErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrPortfolioNotFound),
+		errors.Is(err, domainerr.ErrPortfolioTransactionNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrPortfolioDuplicate):
+		return fiber.StatusConflict, err.Error()
+
+	case errors.Is(err, domainerr.ErrPortfolioQuantityInvalid),
+		errors.Is(err, domainerr.ErrPortfolioPriceInvalid),
+		errors.Is(err, domainerr.ErrPortfolioFeeInvalid),
+		errors.Is(err, domainerr.ErrPortfolioAmountInvalid),
+		errors.Is(err, domainerr.ErrPortfolioRatioInvalid),
+		errors.Is(err, domainerr.ErrPortfolioTradedAtInvalid),
+		errors.Is(err, domainerr.ErrInvalidSymbol),
+		errors.Is(err, domainerr.ErrUnsupportedExchange):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, domainerr.ErrPortfolioInsufficientShares):
+		return fiber.StatusUnprocessableEntity, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, domainerr.ErrServiceTimeout):
+		return fiber.StatusGatewayTimeout, domainerr.ErrServiceTimeout.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapNotificationErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrNotificationNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrNotificationChannelInvalid),
+		errors.Is(err, domainerr.ErrNotificationTargetRequired),
+		errors.Is(err, domainerr.ErrNotificationTargetInvalid),
+		errors.Is(err, domainerr.ErrNotificationEmailNotOwned):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapEmailErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrEmailNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrEmailNotRetryable):
+		return fiber.StatusConflict, err.Error()
+
+	case errors.Is(err, domainerr.ErrEmailStatusInvalid):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapUnderwriterErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	var serviceErr *domainerr.ServiceError
+	switch {
+	case errors.Is(err, domainerr.ErrStockServiceUnavailable):
+		return fiber.StatusServiceUnavailable, err.Error()
+
+	case errors.As(err, &serviceErr):
+		logging.Error(ctx, "stock service error", "error", err)
+		return fiber.StatusBadGateway, domainerr.ErrStockServiceUnavailable.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapCacheErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrInvalidSymbol),
+		errors.Is(err, domainerr.ErrUnsupportedExchange):
+		return fiber.StatusBadRequest, err.Error()
+
+	case errors.Is(err, domainerr.ErrCacheUnavailable):
+		return fiber.StatusServiceUnavailable, err.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
+		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
+	}
+}
+
+func MapCircuitErrorToHTTPStatus(ctx context.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, domainerr.ErrCircuitBreakerNotFound):
+		return fiber.StatusNotFound, err.Error()
+
+	case errors.Is(err, domainerr.ErrCircuitOverrideInvalid):
+		return fiber.StatusBadRequest, err.Error()
+
+	default:
+		logging.Error(ctx, "unexpected error", "error", err)
 		return fiber.StatusInternalServerError, domainerr.ErrInternal.Error()
 	}
 }
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"context"
 	"regexp"
 	"stock_backend/internal/helper"
 	"stock_backend/internal/model/domainerr"
@@ -17,6 +16,10 @@ type FavoriteHandler interface {
 	GetFavorites(c *fiber.Ctx) error
 	AddFavorites(c *fiber.Ctx) error
 	RemoveFavorites(c *fiber.Ctx) error
+	ImportFavorites(c *fiber.Ctx) error
+	ExportFavorites(c *fiber.Ctx) error
+	AddFavoritesBatch(c *fiber.Ctx) error
+	RemoveFavoritesBatch(c *fiber.Ctx) error
 }
 
 type FavoriteHandlerImpl struct {
@@ -32,7 +35,7 @@ func NewFavoriteHandler(service service.FavoriteService, validator *validator.Va
 }
 
 func (handler *FavoriteHandlerImpl) GetFavorites(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := requestContext(c, 2*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -42,7 +45,7 @@ func (handler *FavoriteHandlerImpl) GetFavorites(c *fiber.Ctx) error {
 
 	res, err := handler.Service.GetFavorites(ctx, userId)
 	if err != nil {
-		status, message := MapFavoritesErrorToHTTPStatus(err)
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -50,7 +53,7 @@ func (handler *FavoriteHandlerImpl) GetFavorites(c *fiber.Ctx) error {
 }
 
 func (handler *FavoriteHandlerImpl) AddFavorites(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := requestContext(c, 2*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -69,7 +72,7 @@ func (handler *FavoriteHandlerImpl) AddFavorites(c *fiber.Ctx) error {
 
 	res, err := handler.Service.CreateFavorite(ctx, userId, addFavoriteRequest.UnderwriterId)
 	if err != nil {
-		status, message := MapFavoritesErrorToHTTPStatus(err)
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -77,7 +80,7 @@ func (handler *FavoriteHandlerImpl) AddFavorites(c *fiber.Ctx) error {
 }
 
 func (handler *FavoriteHandlerImpl) RemoveFavorites(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := requestContext(c, 2*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -92,7 +95,103 @@ func (handler *FavoriteHandlerImpl) RemoveFavorites(c *fiber.Ctx) error {
 
 	res, err := handler.Service.RemoveFavorite(ctx, userId, underwriterCode)
 	if err != nil {
-		status, message := MapFavoritesErrorToHTTPStatus(err)
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *FavoriteHandlerImpl) ImportFavorites(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 5*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	underwriterCodes, err := readImportCodes(c, favoriteListFormat)
+	if err != nil {
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	res, err := handler.Service.ImportFavorites(ctx, userId, underwriterCodes)
+	if err != nil {
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *FavoriteHandlerImpl) ExportFavorites(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	underwriterCodes, err := handler.Service.ExportFavorites(ctx, userId)
+	if err != nil {
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return writeExport(c, favoriteListFormat, underwriterCodes)
+}
+
+func (handler *FavoriteHandlerImpl) AddFavoritesBatch(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.BatchFavoriteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.AddFavoritesBatch(ctx, userId, req.UnderwriterIds)
+	if err != nil {
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *FavoriteHandlerImpl) RemoveFavoritesBatch(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.BatchFavoriteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.RemoveFavoritesBatch(ctx, userId, req.UnderwriterIds)
+	if err != nil {
+		status, message := MapFavoritesErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
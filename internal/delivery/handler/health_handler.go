@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"database/sql"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/circuit"
+	"stock_backend/internal/health"
+	"stock_backend/internal/model/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthHandler interface {
+	Liveness(c *fiber.Ctx) error
+	Readiness(c *fiber.Ctx) error
+	GetHealthDetails(c *fiber.Ctx) error
+}
+
+type HealthHandlerImpl struct {
+	Checker *health.Checker
+	DB      *sql.DB
+	Cache   *cache.Cache
+}
+
+func NewHealthHandler(checker *health.Checker, db *sql.DB, cache *cache.Cache) HealthHandler {
+	return &HealthHandlerImpl{
+		Checker: checker,
+		DB:      db,
+		Cache:   cache,
+	}
+}
+
+// Liveness only reports that the process is serving requests, it never calls a dependency
+func (handler *HealthHandlerImpl) Liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(&response.LivenessResponse{Message: "OK"})
+}
+
+// Readiness answers 503 while a critical dependency is down
+func (handler *HealthHandlerImpl) Readiness(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	results := handler.Checker.Run(ctx)
+	res := &response.ReadinessResponse{
+		Message: "Service is ready",
+		Data: response.Readiness{
+			Ready:  health.Ready(results),
+			Checks: newHealthCheckResponses(results, false),
+		},
+	}
+
+	if !res.Data.Ready {
+		res.Message = "Service is not ready"
+		return c.Status(fiber.StatusServiceUnavailable).JSON(res)
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// GetHealthDetails adds the circuit breakers and connection pools to the readiness checks
+func (handler *HealthHandlerImpl) GetHealthDetails(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	results := handler.Checker.Run(ctx)
+
+	breakers := circuit.Breakers()
+	circuitBreakers := make([]response.CircuitBreaker, 0, len(breakers))
+	for _, breaker := range breakers {
+		circuitBreakers = append(circuitBreakers, newCircuitBreakerResponse(breaker))
+	}
+
+	dbStats := handler.DB.Stats()
+	redisStats := handler.Cache.PoolStats()
+
+	res := &response.GetHealthDetailsResponse{
+		Message: "Health details retrieved successfully",
+		Data: response.HealthDetails{
+			Ready:           health.Ready(results),
+			Checks:          newHealthCheckResponses(results, true),
+			CircuitBreakers: circuitBreakers,
+			Database: response.DatabasePoolStats{
+				MaxOpenConnections: dbStats.MaxOpenConnections,
+				OpenConnections:    dbStats.OpenConnections,
+				InUse:              dbStats.InUse,
+				Idle:               dbStats.Idle,
+				WaitCount:          dbStats.WaitCount,
+				WaitDurationMs:     float64(dbStats.WaitDuration) / float64(time.Millisecond),
+				MaxIdleClosed:      dbStats.MaxIdleClosed,
+				MaxIdleTimeClosed:  dbStats.MaxIdleTimeClosed,
+				MaxLifetimeClosed:  dbStats.MaxLifetimeClosed,
+			},
+			Redis: response.RedisPoolStats{
+				Hits:       redisStats.Hits,
+				Misses:     redisStats.Misses,
+				Timeouts:   redisStats.Timeouts,
+				TotalConns: redisStats.TotalConns,
+				IdleConns:  redisStats.IdleConns,
+				StaleConns: redisStats.StaleConns,
+			},
+			Cache: response.CacheStatus{
+				Degraded:             handler.Cache.Degraded(),
+				PendingInvalidations: handler.Cache.PendingInvalidations(),
+			},
+		},
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// newHealthCheckResponses leaves out the error messages unless detailed, they
+// name internal hosts and the readiness probe is not authenticated
+func newHealthCheckResponses(results []health.Result, detailed bool) []response.HealthCheck {
+	checks := make([]response.HealthCheck, 0, len(results))
+	for _, result := range results {
+		check := response.HealthCheck{
+			Name:      result.Name,
+			Status:    result.Status,
+			Critical:  result.Critical,
+			LatencyMs: float64(result.Latency) / float64(time.Millisecond),
+			CheckedAt: result.CheckedAt,
+		}
+		if detailed {
+			check.Error = result.Error
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"stock_backend/internal/model/domainerr"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// listFormat describes how a list of codes is laid out in an import or export file
+type listFormat struct {
+	Column   string // CSV header
+	JSONKey  string // key of the code array in a JSON object
+	Filename string
+}
+
+var (
+	watchlistListFormat = listFormat{Column: "stock", JSONKey: "stocks", Filename: "watchlist"}
+	favoriteListFormat  = listFormat{Column: "underwriter_id", JSONKey: "underwriters", Filename: "favorites"}
+)
+
+// readImportCodes reads the codes from the request body or from an uploaded
+// "file" form field. CSV is used when the content type or file name says so.
+func readImportCodes(c *fiber.Ctx, format listFormat) ([]string, error) {
+	body := c.Body()
+	isCSV := strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv")
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, domainerr.ErrInvalidRequestBody
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, domainerr.ErrInvalidRequestBody
+		}
+		defer file.Close()
+
+		if body, err = io.ReadAll(file); err != nil {
+			return nil, domainerr.ErrInvalidRequestBody
+		}
+		isCSV = strings.EqualFold(filepath.Ext(fileHeader.Filename), ".csv") ||
+			strings.HasPrefix(fileHeader.Header.Get(fiber.HeaderContentType), "text/csv")
+	}
+
+	if isCSV {
+		return parseCSVCodes(body, format.Column)
+	}
+	return parseJSONCodes(body, format.JSONKey)
+}
+
+// parseCSVCodes reads the named column, or the first column when the file has no header
+func parseCSVCodes(body []byte, column string) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, domainerr.ErrImportInvalidFormat
+	}
+
+	index := 0
+	if len(records) > 0 {
+		for i, header := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(header), column) {
+				index = i
+				records = records[1:]
+				break
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(records))
+	for _, record := range records {
+		if index >= len(record) {
+			codes = append(codes, "")
+			continue
+		}
+		codes = append(codes, record[index])
+	}
+	return codes, nil
+}
+
+// parseJSONCodes accepts a bare array of codes or the object written by the export endpoint
+func parseJSONCodes(body []byte, key string) ([]string, error) {
+	trimmed := bytes.TrimSpace(body)
+
+	var codes []string
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &codes); err != nil {
+			return nil, domainerr.ErrImportInvalidFormat
+		}
+		return codes, nil
+	}
+
+	var object map[string][]string
+	if err := json.Unmarshal(trimmed, &object); err != nil {
+		return nil, domainerr.ErrImportInvalidFormat
+	}
+	return object[key], nil
+}
+
+// writeExport sends the codes as a CSV or JSON attachment
+func writeExport(c *fiber.Ctx, format listFormat, codes []string) error {
+	switch c.Query("format", "json") {
+	case "csv":
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write([]string{format.Column})
+		for _, code := range codes {
+			_ = writer.Write([]string{code})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return ResponseErrorJSON(c, fiber.StatusInternalServerError, domainerr.ErrInternal.Error())
+		}
+
+		c.Attachment(format.Filename + ".csv")
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+
+	case "json":
+		c.Attachment(format.Filename + ".json")
+		return c.Status(fiber.StatusOK).JSON(map[string][]string{format.JSONKey: codes})
+
+	default:
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrExportFormatInvalid.Error())
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/service"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler interface {
+	GetNotifications(c *fiber.Ctx) error
+	MarkRead(c *fiber.Ctx) error
+	MarkUnread(c *fiber.Ctx) error
+	MarkAllRead(c *fiber.Ctx) error
+	GetPreferences(c *fiber.Ctx) error
+	UpdatePreference(c *fiber.Ctx) error
+}
+
+type NotificationHandlerImpl struct {
+	Service   service.NotificationService
+	Validator *validator.Validate
+}
+
+func NewNotificationHandler(service service.NotificationService, validator *validator.Validate) NotificationHandler {
+	return &NotificationHandlerImpl{
+		Service:   service,
+		Validator: validator,
+	}
+}
+
+func (handler *NotificationHandlerImpl) GetNotifications(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.GetNotifications(ctx, userId, c.QueryBool("unread"))
+	if err != nil {
+		status, message := MapNotificationErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *NotificationHandlerImpl) MarkRead(c *fiber.Ctx) error {
+	return handler.setRead(c, true)
+}
+
+func (handler *NotificationHandlerImpl) MarkUnread(c *fiber.Ctx) error {
+	return handler.setRead(c, false)
+}
+
+func (handler *NotificationHandlerImpl) setRead(c *fiber.Ctx, read bool) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	notificationId := c.Params("id")
+	if _, err := uuid.Parse(notificationId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrNotificationIdInvalid.Error())
+	}
+
+	res, err := handler.Service.MarkRead(ctx, userId, notificationId, read)
+	if err != nil {
+		status, message := MapNotificationErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *NotificationHandlerImpl) MarkAllRead(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.MarkAllRead(ctx, userId)
+	if err != nil {
+		status, message := MapNotificationErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *NotificationHandlerImpl) GetPreferences(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.GetPreferences(ctx, userId)
+	if err != nil {
+		status, message := MapNotificationErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *NotificationHandlerImpl) UpdatePreference(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.UpdateNotificationPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.UpdatePreference(ctx, userId, c.Params("channel"), req)
+	if err != nil {
+		status, message := MapNotificationErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/service"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PortfolioHandler interface {
+	CreatePortfolio(c *fiber.Ctx) error
+	GetPortfolios(c *fiber.Ctx) error
+	GetPortfolio(c *fiber.Ctx) error
+	UpdatePortfolio(c *fiber.Ctx) error
+	DeletePortfolio(c *fiber.Ctx) error
+	CreateTransaction(c *fiber.Ctx) error
+	GetTransactions(c *fiber.Ctx) error
+	DeleteTransaction(c *fiber.Ctx) error
+	GetHoldings(c *fiber.Ctx) error
+	GetSummary(c *fiber.Ctx) error
+}
+
+type PortfolioHandlerImpl struct {
+	Service   service.PortfolioService
+	Validator *validator.Validate
+}
+
+func NewPortfolioHandler(service service.PortfolioService, validator *validator.Validate) PortfolioHandler {
+	return &PortfolioHandlerImpl{
+		Service:   service,
+		Validator: validator,
+	}
+}
+
+func (handler *PortfolioHandlerImpl) CreatePortfolio(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.CreatePortfolioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.CreatePortfolio(ctx, userId, req)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) GetPortfolios(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.GetPortfolios(ctx, userId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) GetPortfolio(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	res, err := handler.Service.GetPortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) UpdatePortfolio(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	var req request.UpdatePortfolioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.UpdatePortfolio(ctx, userId, portfolioId, req)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) DeletePortfolio(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	res, err := handler.Service.DeletePortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) CreateTransaction(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	var req request.CreateTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.CreateTransaction(ctx, userId, portfolioId, req)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) GetTransactions(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	res, err := handler.Service.GetTransactions(ctx, userId, portfolioId, c.Query("stock"))
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) DeleteTransaction(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	transactionId := c.Params("transactionId")
+	if _, err := uuid.Parse(transactionId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioTransactionIdInvalid.Error())
+	}
+
+	res, err := handler.Service.DeleteTransaction(ctx, userId, portfolioId, transactionId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) GetHoldings(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 3*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	res, err := handler.Service.GetHoldings(ctx, userId, portfolioId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *PortfolioHandlerImpl) GetSummary(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 3*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	portfolioId := c.Params("id")
+	if _, err := uuid.Parse(portfolioId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrPortfolioIdInvalid.Error())
+	}
+
+	res, err := handler.Service.GetSummary(ctx, userId, portfolioId)
+	if err != nil {
+		status, message := MapPortfolioErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"stock_backend/internal/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UnderwriterHandler interface {
+	GetUnderwriters(c *fiber.Ctx) error
+	SyncUnderwriters(c *fiber.Ctx) error
+}
+
+type UnderwriterHandlerImpl struct {
+	Service service.UnderwriterService
+}
+
+func NewUnderwriterHandler(service service.UnderwriterService) UnderwriterHandler {
+	return &UnderwriterHandlerImpl{
+		Service: service,
+	}
+}
+
+func (handler *UnderwriterHandlerImpl) GetUnderwriters(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.GetUnderwriters(ctx, c.Query("search"))
+	if err != nil {
+		status, message := MapUnderwriterErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *UnderwriterHandlerImpl) SyncUnderwriters(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 10*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.SyncUnderwriters(ctx)
+	if err != nil {
+		status, message := MapUnderwriterErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
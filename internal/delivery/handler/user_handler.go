@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"context"
 	"time"
 
 	"stock_backend/internal/helper"
@@ -35,7 +34,7 @@ func NewUserHandler(service service.UserService, validator *validator.Validate)
 }
 
 func (handler *UserHandlerImpl) Login(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	var loginRequest request.LoginRequest
@@ -49,7 +48,7 @@ func (handler *UserHandlerImpl) Login(c *fiber.Ctx) error {
 
 	res, err := handler.UserService.Login(ctx, loginRequest)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -57,7 +56,7 @@ func (handler *UserHandlerImpl) Login(c *fiber.Ctx) error {
 }
 
 func (handler *UserHandlerImpl) Register(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	var registerRequest request.RegisterRequest
@@ -69,9 +68,14 @@ func (handler *UserHandlerImpl) Register(c *fiber.Ctx) error {
 		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
 	}
 
+	// Fall back to the device language when the app doesn't send a locale
+	if registerRequest.Locale == "" {
+		registerRequest.Locale = c.AcceptsLanguages(service.SupportedLocales...)
+	}
+
 	res, err := handler.UserService.Register(ctx, registerRequest)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -79,7 +83,7 @@ func (handler *UserHandlerImpl) Register(c *fiber.Ctx) error {
 }
 
 func (handler *UserHandlerImpl) VerifyUser(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := requestContext(c, 2*time.Second)
 	defer cancel()
 
 	token := c.Query("token")
@@ -89,7 +93,7 @@ func (handler *UserHandlerImpl) VerifyUser(c *fiber.Ctx) error {
 
 	res, err := handler.UserService.VerifyUser(ctx, token)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -97,7 +101,7 @@ func (handler *UserHandlerImpl) VerifyUser(c *fiber.Ctx) error {
 }
 
 func (handler *UserHandlerImpl) Logout(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -107,7 +111,7 @@ func (handler *UserHandlerImpl) Logout(c *fiber.Ctx) error {
 
 	res, err := handler.UserService.Logout(ctx, userId)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -115,7 +119,7 @@ func (handler *UserHandlerImpl) Logout(c *fiber.Ctx) error {
 }
 
 func (handler *UserHandlerImpl) DeleteUser(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	var deleteRequest request.DeleteUserRequest
@@ -129,7 +133,7 @@ func (handler *UserHandlerImpl) DeleteUser(c *fiber.Ctx) error {
 
 	res, err := handler.UserService.DeleteUser(ctx, deleteRequest.UserId)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -137,7 +141,7 @@ func (handler *UserHandlerImpl) DeleteUser(c *fiber.Ctx) error {
 }
 
 func (handler *UserHandlerImpl) GetUserInfo(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -147,7 +151,7 @@ func (handler *UserHandlerImpl) GetUserInfo(c *fiber.Ctx) error {
 
 	res, err := handler.UserService.GetProfile(ctx, userId)
 	if err != nil {
-		status, message := MapErrorToHTTPStatus(err)
+		status, message := MapErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"context"
 	"errors"
+	"stock_backend/internal/entity"
 	"stock_backend/internal/helper"
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/request"
@@ -18,6 +18,11 @@ type WatchlistHandler interface {
 	GetWatchlist(c *fiber.Ctx) error
 	AddWatchlist(c *fiber.Ctx) error
 	RemoveWatchlist(c *fiber.Ctx) error
+	UpdateWatchlist(c *fiber.Ctx) error
+	ImportWatchlist(c *fiber.Ctx) error
+	ExportWatchlist(c *fiber.Ctx) error
+	AddWatchlistBatch(c *fiber.Ctx) error
+	RemoveWatchlistBatch(c *fiber.Ctx) error
 }
 
 type WatchlistHandlerImpl struct {
@@ -33,17 +38,43 @@ func NewWatchlistHandler(service service.WatchlistService, validator *validator.
 }
 
 func (handler *WatchlistHandlerImpl) GetWatchlist(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
-	defer cancel()
-
 	userId, ok := helper.GetUserID(c)
 	if !ok {
 		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
 	}
 
-	res, err := handler.Service.GetWatchlist(ctx, userId)
+	filter := entity.WatchlistFilter{
+		Tag:  c.Query("tag"),
+		Sort: c.Query("sort"),
+	}
+
+	switch c.Query("include") {
+	case "":
+	case "quote":
+		return handler.getWatchlistWithQuotes(c, userId, filter)
+	default:
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidInclude.Error())
+	}
+
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.GetWatchlist(ctx, userId, filter)
 	if err != nil {
-		status, message := MapWatchlistErrorToHTTPStatus(err)
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistHandlerImpl) getWatchlistWithQuotes(c *fiber.Ctx, userId string, filter entity.WatchlistFilter) error {
+	ctx, cancel := requestContext(c, 3*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.GetWatchlistWithQuotes(ctx, userId, filter)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -51,7 +82,7 @@ func (handler *WatchlistHandlerImpl) GetWatchlist(c *fiber.Ctx) error {
 }
 
 func (handler *WatchlistHandlerImpl) AddWatchlist(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 3*time.Second)
+	ctx, cancel := requestContext(c, 3*time.Second)
 	defer cancel()
 
 	userId, ok := helper.GetUserID(c)
@@ -76,7 +107,7 @@ func (handler *WatchlistHandlerImpl) AddWatchlist(c *fiber.Ctx) error {
 				Message: serviceErr.Message,
 			})
 		}
-		status, message := MapWatchlistErrorToHTTPStatus(err)
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
 
 		return ResponseErrorJSON(c, status, message)
 	}
@@ -85,7 +116,7 @@ func (handler *WatchlistHandlerImpl) AddWatchlist(c *fiber.Ctx) error {
 }
 
 func (handler *WatchlistHandlerImpl) RemoveWatchlist(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := requestContext(c, 2*time.Second)
 	defer cancel()
 
 	stock := c.Params("stock")
@@ -96,7 +127,131 @@ func (handler *WatchlistHandlerImpl) RemoveWatchlist(c *fiber.Ctx) error {
 
 	res, err := handler.Service.RemoveFromWatchlist(ctx, userId, stock)
 	if err != nil {
-		status, message := MapWatchlistErrorToHTTPStatus(err)
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistHandlerImpl) UpdateWatchlist(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.UpdateWatchlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.UpdateWatchlistEntry(ctx, userId, c.Params("stock"), req)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistHandlerImpl) ImportWatchlist(c *fiber.Ctx) error {
+	// Every row is checked with the stock service, so allow more time than a single add
+	ctx, cancel := requestContext(c, 10*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	stocks, err := readImportCodes(c, watchlistListFormat)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	res, err := handler.Service.ImportWatchlist(ctx, userId, stocks)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistHandlerImpl) ExportWatchlist(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	stocks, err := handler.Service.ExportWatchlist(ctx, userId)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return writeExport(c, watchlistListFormat, stocks)
+}
+
+func (handler *WatchlistHandlerImpl) AddWatchlistBatch(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 5*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.BatchWatchlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.AddToWatchlistBatch(ctx, userId, req.Stocks)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistHandlerImpl) RemoveWatchlistBatch(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.BatchWatchlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.RemoveFromWatchlistBatch(ctx, userId, req.Stocks)
+	if err != nil {
+		status, message := MapWatchlistErrorToHTTPStatus(c.UserContext(), err)
 		return ResponseErrorJSON(c, status, message)
 	}
 
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/service"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WatchlistShareHandler interface {
+	CreateShare(c *fiber.Ctx) error
+	GetShares(c *fiber.Ctx) error
+	RotateShare(c *fiber.Ctx) error
+	RevokeShare(c *fiber.Ctx) error
+	GetPublicWatchlist(c *fiber.Ctx) error
+}
+
+type WatchlistShareHandlerImpl struct {
+	Service   service.WatchlistShareService
+	Validator *validator.Validate
+}
+
+func NewWatchlistShareHandler(service service.WatchlistShareService, validator *validator.Validate) WatchlistShareHandler {
+	return &WatchlistShareHandlerImpl{
+		Service:   service,
+		Validator: validator,
+	}
+}
+
+func (handler *WatchlistShareHandlerImpl) CreateShare(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	var req request.CreateWatchlistShareRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrInvalidRequestBody.Error())
+	}
+
+	if err := handler.Validator.Struct(req); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, helper.ValidationError(err))
+	}
+
+	res, err := handler.Service.CreateShare(ctx, userId, req)
+	if err != nil {
+		status, message := MapWatchlistShareErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (handler *WatchlistShareHandlerImpl) GetShares(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	res, err := handler.Service.GetShares(ctx, userId)
+	if err != nil {
+		status, message := MapWatchlistShareErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistShareHandlerImpl) RotateShare(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	shareId := c.Params("id")
+	if _, err := uuid.Parse(shareId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrWatchlistShareIdInvalid.Error())
+	}
+
+	res, err := handler.Service.RotateShare(ctx, userId, shareId)
+	if err != nil {
+		status, message := MapWatchlistShareErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (handler *WatchlistShareHandlerImpl) RevokeShare(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	userId, ok := helper.GetUserID(c)
+	if !ok {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrFavoritesUserIdRequired.Error())
+	}
+
+	shareId := c.Params("id")
+	if _, err := uuid.Parse(shareId); err != nil {
+		return ResponseErrorJSON(c, fiber.StatusBadRequest, domainerr.ErrWatchlistShareIdInvalid.Error())
+	}
+
+	res, err := handler.Service.RevokeShare(ctx, userId, shareId)
+	if err != nil {
+		status, message := MapWatchlistShareErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// GetPublicWatchlist serves a shared watchlist without authentication
+func (handler *WatchlistShareHandlerImpl) GetPublicWatchlist(c *fiber.Ctx) error {
+	ctx, cancel := requestContext(c, 2*time.Second)
+	defer cancel()
+
+	res, err := handler.Service.GetPublicWatchlist(ctx, c.Params("slug"))
+	if err != nil {
+		status, message := MapWatchlistShareErrorToHTTPStatus(c.UserContext(), err)
+		return ResponseErrorJSON(c, status, message)
+	}
+
+	// The list can change or be revoked at any time, so shared caches must not keep it
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"stock_backend/internal/delivery/handler"
 	"stock_backend/internal/helper"
+	"stock_backend/internal/logging"
 	"stock_backend/internal/model/domainerr"
 	"strings"
 	"time"
@@ -44,6 +45,7 @@ func JWTMiddleware(secretKey string) fiber.Handler {
 			return handler.ResponseErrorJSON(c, fiber.StatusUnauthorized, domainerr.ErrInvalidTokenClaims.Error())
 		}
 		c.Locals("userId", sub)
+		c.SetUserContext(logging.With(c.UserContext(), "user_id", sub))
 
 		role, ok := claims["role"].(string)
 		if !ok {
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log/slog"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/logging"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoggingMiddleware gives every request a logger carrying its request ID, which
+// the JWT middleware and the handlers extend with the user and the route, and
+// logs one line per request once it is answered.
+func LoggingMiddleware(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		start := time.Now()
+		ctx := logging.With(c.UserContext(), "request_id", helper.GetRequestID(c.UserContext()))
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		route, status := routeAndStatus(c, err)
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		// The logger of ctx only has the request ID, the user and the route are added once here
+		userId, _ := helper.GetUserID(c)
+		logging.FromContext(ctx).Log(ctx, level, "request completed",
+			"user_id", userId,
+			"method", c.Method(),
+			"route", route,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+		return err
+	})
+}
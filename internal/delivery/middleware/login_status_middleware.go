@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"os"
 	"stock_backend/internal/delivery/handler"
 	"stock_backend/internal/model/domainerr"
 	"strings"
@@ -11,7 +10,7 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-func LoggedOutMiddleware() fiber.Handler {
+func LoggedOutMiddleware(jwtSecret string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		auth := c.Get("Authorization")
 
@@ -27,7 +26,6 @@ func LoggedOutMiddleware() fiber.Handler {
 		tokenStr := parts[1]
 
 		// Parse and validate the JWT token
-		jwtSecret := os.Getenv("JWT_SECRET")
 		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
 			// Check if the signing method is HMAC
 			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route template, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpRequestDuration)
+}
+
+// MetricsMiddleware records every request under the template of the route that
+// answered it, such as /api/v1/watchlist/:stock, so the labels stay bounded. A
+// request stopped by a middleware is recorded under the prefix of that middleware.
+func MetricsMiddleware(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		route, status := routeAndStatus(c, err)
+		labels := prometheus.Labels{
+			"route":  route,
+			"method": c.Method(),
+			"status": strconv.Itoa(status),
+		}
+		httpRequests.With(labels).Inc()
+		httpRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+		return err
+	})
+}
+
+// routeAndStatus returns the route template that answered the request and the
+// status it answered with. The error handler writes the response after the
+// middlewares return, so the status of an error is taken from the error.
+func routeAndStatus(c *fiber.Ctx, err error) (string, int) {
+	status := c.Response().StatusCode()
+	route := c.Route().Path
+	if err == nil {
+		return route, status
+	}
+
+	status = fiber.StatusInternalServerError
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		status = fiberErr.Code
+	}
+
+	// The router answers these itself, the request path must not become a label
+	if status == fiber.StatusNotFound || status == fiber.StatusMethodNotAllowed {
+		route = "unmatched"
+	}
+	return route, status
+}
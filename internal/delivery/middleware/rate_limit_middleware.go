@@ -1,11 +1,9 @@
 package middleware
 
 import (
-	"log"
-	"os"
+	"stock_backend/config"
 	"stock_backend/internal/delivery/handler"
 	"stock_backend/internal/model/domainerr"
-	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,16 +12,11 @@ import (
 	"github.com/gofiber/storage/redis/v3"
 )
 
-func RateLimitMiddleware(app *fiber.App) {
-	port, err := strconv.Atoi(os.Getenv("REDIS_PORT"))
-	if err != nil {
-		log.Fatal("dailed to load get port")
-	}
-
+func RateLimitMiddleware(app *fiber.App, cfg config.Redis) {
 	store := redis.New(redis.Config{
-		Host:     os.Getenv("REDIS_HOST"),
-		Password: os.Getenv("REDIS_PASSWORD"),
-		Port:     port,
+		Host:     cfg.Host,
+		Password: cfg.Password,
+		Port:     cfg.Port,
 	})
 
 	app.Use(limiter.New(limiter.Config{
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"stock_backend/internal/helper"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
+)
+
+// RequestIDMiddleware keeps the X-Request-ID of the gateway, or assigns one, so
+// calls to other services can be correlated with the request that made them.
+// An ID that is not a UUID or a short token is replaced, it would otherwise be
+// copied into the logs and the headers of outgoing calls as sent.
+// Handlers derive their context from c.UserContext(), which carries the ID for
+// helper.GetRequestID.
+func RequestIDMiddleware(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		if requestId := c.Get(helper.RequestIDHeader); requestId != "" && !helper.ValidRequestID(requestId) {
+			c.Request().Header.Del(helper.RequestIDHeader)
+		}
+		return c.Next()
+	})
+
+	app.Use(requestid.New(requestid.Config{
+		Header:     helper.RequestIDHeader,
+		Generator:  uuid.NewString,
+		ContextKey: helper.RequestIDKey,
+	}))
+
+	app.Use(func(c *fiber.Ctx) error {
+		requestId, _ := c.Locals(helper.RequestIDKey).(string)
+		c.SetUserContext(helper.WithRequestID(c.UserContext(), requestId))
+		return c.Next()
+	})
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"stock_backend/internal/helper"
+	"stock_backend/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware opens the server span of every request, continuing the
+// trace of the gateway when it sends a traceparent header. Handlers pick the
+// span up from c.UserContext().
+func TracingMiddleware(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		ctx, span := tracing.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				attribute.String("request.id", helper.GetRequestID(ctx)),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		// The route is only known once a handler matched
+		route, status := routeAndStatus(c, err)
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	})
+}
+
+// headerCarrier reads and writes the trace context in the request headers
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (carrier headerCarrier) Get(key string) string {
+	return carrier.c.Get(key)
+}
+
+func (carrier headerCarrier) Set(key string, value string) {
+	carrier.c.Request().Header.Set(key, value)
+}
+
+func (carrier headerCarrier) Keys() []string {
+	keys := []string{}
+	carrier.c.Request().Header.VisitAll(func(key []byte, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"database/sql"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAdminRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, redisCache *cache.Cache, stockClient client.StockCache) {
+	emailOutboxRepository := repository.NewEmailOutboxRepository(db)
+	emailOutboxService := service.NewEmailOutboxService(emailOutboxRepository)
+	emailOutboxHandler := handler.NewEmailOutboxHandler(emailOutboxService)
+
+	underwriterService := service.NewUnderwriterService(repository.NewUnderwriterRepository(db), stockClient)
+	underwriterHandler := handler.NewUnderwriterHandler(underwriterService)
+	cacheHandler := handler.NewCacheHandler(redisCache, stockClient)
+	circuitHandler := handler.NewCircuitHandler()
+
+	adminRouting := router.Group("/api/v1/admin")
+	adminRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret), middleware.AdminMiddleware())
+	adminRouting.Get("/emails", emailOutboxHandler.GetEmails)
+	adminRouting.Post("/emails/:id/retry", emailOutboxHandler.RetryEmail)
+	adminRouting.Post("/underwriters/sync", underwriterHandler.SyncUnderwriters)
+	adminRouting.Get("/cache", cacheHandler.GetCacheStatus)
+	adminRouting.Delete("/cache/stocks", cacheHandler.PurgeStockCache)
+	adminRouting.Get("/circuit-breakers", circuitHandler.GetCircuitBreakers)
+	adminRouting.Put("/circuit-breakers/:name/override", circuitHandler.SetCircuitOverride)
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"database/sql"
+	"stock_backend/config"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAlertRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate) {
+	alertRepository := repository.NewAlertRepository(db)
+	alertService := service.NewAlertService(alertRepository)
+	alertHandler := handler.NewAlertHandler(alertService, validator)
+
+	alertRouting := router.Group("/api/v1/alerts")
+	alertRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
+	alertRouting.Get("", alertHandler.GetAlerts)
+	alertRouting.Post("", alertHandler.CreateAlert)
+	alertRouting.Patch("/:id", alertHandler.UpdateAlert)
+	alertRouting.Delete("/:id", alertHandler.DeleteAlert)
+	alertRouting.Get("/:id/events", alertHandler.GetAlertEvents)
+}
@@ -2,7 +2,8 @@ package router
 
 import (
 	"database/sql"
-	"os"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/delivery/handler"
 	"stock_backend/internal/delivery/middleware"
 	"stock_backend/internal/repository"
@@ -10,17 +11,25 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/redis/go-redis/v9"
 )
 
-func RegisterFavoriteRoutes(router fiber.Router, db *sql.DB, validator *validator.Validate, redis_db *redis.Client) {
-	favoriteRepository := repository.NewFavoriteRepository(db, redis_db)
-	favoriteService := service.NewFavoriteService(favoriteRepository)
+func RegisterFavoriteRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate, redisCache *cache.Cache) {
+	favoriteRepository := repository.NewFavoriteRepository(db, redisCache)
+	favoriteService := service.NewFavoriteService(favoriteRepository, repository.NewUnderwriterRepository(db))
 	favoriteHandler := handler.NewFavoriteHandler(favoriteService, validator)
 
+	jwtMiddleware := middleware.JWTMiddleware(cfg.JWT.Secret)
+	userMiddleware := middleware.UserMiddleware()
+
+	// A group always joins paths with "/", so the batch routes are registered on the router itself
+	router.Post("/api/v1/favorites\\:batch", jwtMiddleware, userMiddleware, favoriteHandler.AddFavoritesBatch)
+	router.Delete("/api/v1/favorites\\:batch", jwtMiddleware, userMiddleware, favoriteHandler.RemoveFavoritesBatch)
+
 	favoriteRouting := router.Group("/api/v1/favorites")
-	favoriteRouting.Use(middleware.JWTMiddleware(os.Getenv("JWT_SECRET")), middleware.UserMiddleware())
+	favoriteRouting.Use(jwtMiddleware, userMiddleware)
 	favoriteRouting.Get("", favoriteHandler.GetFavorites)
 	favoriteRouting.Post("", favoriteHandler.AddFavorites)
+	favoriteRouting.Post("/import", favoriteHandler.ImportFavorites)
+	favoriteRouting.Get("/export", favoriteHandler.ExportFavorites)
 	favoriteRouting.Delete("/:underwriter", favoriteHandler.RemoveFavorites)
 }
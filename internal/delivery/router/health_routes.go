@@ -0,0 +1,40 @@
+package router
+
+import (
+	"database/sql"
+	"net/http"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/health"
+	"stock_backend/internal/mailer"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterHealthRoutes is called before the logger and the rate limiter so
+// probes are neither logged nor limited. The rate limiter keeps its counters
+// in Redis and would fail the probes while Redis is down.
+func RegisterHealthRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, redisCache *cache.Cache, emailSender mailer.EmailSender) {
+	checks := []health.Check{
+		health.PostgresCheck(db),
+		health.RedisCheck(redisCache),
+		health.StockServiceCheck(&http.Client{}, client.NewStockRoutes(cfg.StockService)),
+	}
+	if pinger, ok := emailSender.(health.Pinger); ok {
+		checks = append(checks, health.SMTPCheck(pinger))
+	}
+
+	checker := health.NewChecker(
+		cfg.Health.CheckTimeout,
+		cfg.Health.CacheTTL,
+		checks...,
+	)
+	healthHandler := handler.NewHealthHandler(checker, db, redisCache)
+
+	router.Get("/healthz", healthHandler.Liveness)
+	router.Get("/readyz", healthHandler.Readiness)
+	router.Get("/health/details", middleware.JWTMiddleware(cfg.JWT.Secret), middleware.AdminMiddleware(), healthHandler.GetHealthDetails)
+}
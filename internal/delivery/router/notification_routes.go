@@ -0,0 +1,28 @@
+package router
+
+import (
+	"database/sql"
+	"stock_backend/config"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterNotificationRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate) {
+	notificationRepository := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepository)
+	notificationHandler := handler.NewNotificationHandler(notificationService, validator)
+
+	notificationRouting := router.Group("/api/v1/notifications")
+	notificationRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
+	notificationRouting.Get("", notificationHandler.GetNotifications)
+	notificationRouting.Post("/read-all", notificationHandler.MarkAllRead)
+	notificationRouting.Get("/preferences", notificationHandler.GetPreferences)
+	notificationRouting.Put("/preferences/:channel", notificationHandler.UpdatePreference)
+	notificationRouting.Patch("/:id/read", notificationHandler.MarkRead)
+	notificationRouting.Patch("/:id/unread", notificationHandler.MarkUnread)
+}
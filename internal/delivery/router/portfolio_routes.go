@@ -0,0 +1,33 @@
+package router
+
+import (
+	"database/sql"
+	"stock_backend/config"
+	"stock_backend/internal/client"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterPortfolioRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate, stockClient client.StockClient) {
+	portfolioRepository := repository.NewPortfolioRepository(db)
+	portfolioService := service.NewPortfolioService(portfolioRepository, stockClient)
+	portfolioHandler := handler.NewPortfolioHandler(portfolioService, validator)
+
+	portfolioRouting := router.Group("/api/v1/portfolios")
+	portfolioRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
+	portfolioRouting.Get("", portfolioHandler.GetPortfolios)
+	portfolioRouting.Post("", portfolioHandler.CreatePortfolio)
+	portfolioRouting.Get("/:id", portfolioHandler.GetPortfolio)
+	portfolioRouting.Patch("/:id", portfolioHandler.UpdatePortfolio)
+	portfolioRouting.Delete("/:id", portfolioHandler.DeletePortfolio)
+	portfolioRouting.Get("/:id/transactions", portfolioHandler.GetTransactions)
+	portfolioRouting.Post("/:id/transactions", portfolioHandler.CreateTransaction)
+	portfolioRouting.Delete("/:id/transactions/:transactionId", portfolioHandler.DeleteTransaction)
+	portfolioRouting.Get("/:id/holdings", portfolioHandler.GetHoldings)
+	portfolioRouting.Get("/:id/summary", portfolioHandler.GetSummary)
+}
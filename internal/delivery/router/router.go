@@ -2,17 +2,26 @@ package router
 
 import (
 	"database/sql"
+	"log/slog"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client"
 	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/mailer"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/redis/go-redis/v9"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func SetupRouter(db *sql.DB, redisDB *redis.Client) *fiber.App {
+func SetupRouter(cfg *config.Config, db *sql.DB, redisCache *cache.Cache, emailSender mailer.EmailSender, stockClient client.StockCache) *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName:               "Stock Backend API",
 		IdleTimeout:           5 * time.Second,
@@ -30,15 +39,45 @@ func SetupRouter(db *sql.DB, redisDB *redis.Client) *fiber.App {
 	})
 
 	// Middleware setup
-	app.Use(logger.New())
+	middleware.RequestIDMiddleware(app)
+	middleware.TracingMiddleware(app)
+	middleware.MetricsMiddleware(app)
+	RegisterHealthRoutes(app, cfg, db, redisCache, emailSender)
+	middleware.LoggingMiddleware(app)
 	middleware.CorsMiddleware(app)
-	middleware.RateLimitMiddleware(app)
+	middleware.RateLimitMiddleware(app, cfg.Redis)
 
 	validator := validator.New()
+	if err := helper.RegisterSymbolValidation(validator, cfg.StockService.SymbolFormats); err != nil {
+		logging.Fatal("register symbol validation failed", "error", err)
+	}
 
 	// Register Route
-	RegisterUserRoutes(app, db, validator, redisDB)
-	RegisterWatchlistRoutes(app, db, validator)
-	RegisterFavoriteRoutes(app, db, validator, redisDB)
+	RegisterUserRoutes(app, cfg, db, validator, redisCache)
+	RegisterWatchlistRoutes(app, cfg, db, validator, redisCache, stockClient)
+	RegisterFavoriteRoutes(app, cfg, db, validator, redisCache)
+	RegisterUnderwriterRoutes(app, cfg, db)
+	RegisterAlertRoutes(app, cfg, db, validator)
+	RegisterPortfolioRoutes(app, cfg, db, validator, stockClient)
+	RegisterNotificationRoutes(app, cfg, db, validator)
+	RegisterAdminRoutes(app, cfg, db, redisCache, stockClient)
+	return app
+}
+
+// SetupMetricsRouter serves /metrics on its own app, which main listens on at
+// METRICS_PORT. Only the deployment reaches that port, so the metrics are not
+// exposed next to the API.
+func SetupMetricsRouter(db *sql.DB) *fiber.App {
+	app := fiber.New(fiber.Config{
+		AppName:               "Stock Backend Metrics",
+		ReadTimeout:           5 * time.Second,
+		WriteTimeout:          5 * time.Second,
+		DisableStartupMessage: true,
+	})
+
+	if err := prometheus.Register(collectors.NewDBStatsCollector(db, "postgres")); err != nil {
+		slog.Error("register database metrics failed", "error", err)
+	}
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 	return app
 }
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"database/sql"
+	"stock_backend/config"
+	"stock_backend/internal/delivery/handler"
+	"stock_backend/internal/delivery/middleware"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterUnderwriterRoutes(router fiber.Router, cfg *config.Config, db *sql.DB) {
+	underwriterRepository := repository.NewUnderwriterRepository(db)
+	// Reads never reach the stock service, so no client is needed here
+	underwriterService := service.NewUnderwriterService(underwriterRepository, nil)
+	underwriterHandler := handler.NewUnderwriterHandler(underwriterService)
+
+	underwriterRouting := router.Group("/api/v1/underwriters")
+	underwriterRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
+	underwriterRouting.Get("", underwriterHandler.GetUnderwriters)
+}
@@ -2,8 +2,8 @@ package router
 
 import (
 	"database/sql"
-	"log"
-	"os"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/delivery/handler"
 	"stock_backend/internal/delivery/middleware"
 	"stock_backend/internal/repository"
@@ -11,28 +11,26 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/redis/go-redis/v9"
 )
 
-func RegisterUserRoutes(router fiber.Router, db *sql.DB, validator *validator.Validate, redis_db *redis.Client) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	userRepository := repository.NewUserRepository(db, redis_db)
-
-	smtp, err := service.LoadSMTPConfig()
-	if err != nil {
-		log.Printf("[ERROR] error load SMTP: %v", err)
+func RegisterUserRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate, redisCache *cache.Cache) {
+	userRepository := repository.NewUserRepository(db, redisCache)
+	emailConfig := service.EmailConfig{
+		AppHost: cfg.App.Host,
+		AppPort: cfg.App.Port,
+		Secret:  cfg.Email.SecretKey,
 	}
-	userService := service.NewUserService(userRepository, jwtSecret, smtp)
+	userService := service.NewUserService(userRepository, cfg.JWT.Secret, emailConfig)
 	userHandler := handler.NewUserHandler(userService, validator)
 
 	userRouting := router.Group("/api/v1/auth")
-	userRouting.Use(middleware.LoggedOutMiddleware())
+	userRouting.Use(middleware.LoggedOutMiddleware(cfg.JWT.Secret))
 	userRouting.Post("/login", userHandler.Login)
 	userRouting.Post("/register", userHandler.Register)
 	userRouting.Get("/verify", userHandler.VerifyUser)
 
 	authRouting := router.Group("/api/v1/users")
-	authRouting.Use(middleware.JWTMiddleware(jwtSecret))
+	authRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
 	authRouting.Get("/profile", userHandler.GetUserInfo)
 	authRouting.Post("/logout", userHandler.Logout)
 
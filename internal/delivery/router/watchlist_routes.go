@@ -2,7 +2,8 @@ package router
 
 import (
 	"database/sql"
-	"os"
+	"stock_backend/config"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/circuit"
 	"stock_backend/internal/client"
 	"stock_backend/internal/delivery/handler"
@@ -14,16 +15,50 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterWatchlistRoutes(router fiber.Router, db *sql.DB, validator *validator.Validate) {
-	watchlistRepository := repository.NewWatchlistRepository(db)
-	breaker := circuit.NewCircuitBreaker("stock-service")
-	stockClient := client.NewStockClient(os.Getenv("STOCK_SERVICE_URL"), breaker)
+func RegisterWatchlistRoutes(router fiber.Router, cfg *config.Config, db *sql.DB, validator *validator.Validate, redisCache *cache.Cache, stockClient client.StockCache) {
+	watchlistRepository := repository.NewCachedWatchlistRepository(
+		repository.NewWatchlistRepository(db),
+		redisCache,
+		cfg.Cache.WatchlistTTL,
+	)
 	watchlistService := service.NewWatchlistService(watchlistRepository, stockClient)
 	watchlistHandler := handler.NewWatchlistHandler(watchlistService, validator)
+	shareRepository := repository.NewWatchlistShareRepository(db)
+	shareService := service.NewWatchlistShareService(shareRepository, watchlistRepository)
+	shareHandler := handler.NewWatchlistShareHandler(shareService, validator)
 
 	authRouting := router.Group("/api/v1/watchlists")
-	authRouting.Use(middleware.JWTMiddleware(os.Getenv("JWT_SECRET")))
+	authRouting.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
 	authRouting.Get("", watchlistHandler.GetWatchlist)
 	authRouting.Post("/stocks", watchlistHandler.AddWatchlist)
+	authRouting.Post("/stocks\\:batch", watchlistHandler.AddWatchlistBatch)
+	authRouting.Delete("/stocks\\:batch", watchlistHandler.RemoveWatchlistBatch)
 	authRouting.Delete("/stocks/:stock", watchlistHandler.RemoveWatchlist)
+	authRouting.Patch("/stocks/:stock", watchlistHandler.UpdateWatchlist)
+	authRouting.Post("/import", watchlistHandler.ImportWatchlist)
+	authRouting.Get("/export", watchlistHandler.ExportWatchlist)
+	authRouting.Get("/shares", shareHandler.GetShares)
+	authRouting.Post("/shares", shareHandler.CreateShare)
+	authRouting.Post("/shares/:id/rotate", shareHandler.RotateShare)
+	authRouting.Delete("/shares/:id", shareHandler.RevokeShare)
+
+	publicRouting := router.Group("/api/v1/public/watchlists")
+	publicRouting.Get("/:slug", shareHandler.GetPublicWatchlist)
+}
+
+// NewStockCache builds the stock client whose existence checks are cached in
+// Redis. It is built once and shared so each exchange service has a single breaker.
+func NewStockCache(cfg *config.Config, redisCache *cache.Cache) client.StockCache {
+	newBreaker := func(name string) client.Breaker {
+		return circuit.NewCircuitBreaker(name, cfg.CircuitBreaker(name))
+	}
+	return client.NewCachedStockClient(
+		client.NewStockClient(client.NewStockRoutes(cfg.StockService), newBreaker),
+		redisCache,
+		client.StockCacheTTL{
+			Found:    cfg.Cache.StockTTL,
+			NotFound: cfg.Cache.StockNotFoundTTL,
+			Stale:    cfg.Cache.StockStaleTTL,
+		},
+	)
 }
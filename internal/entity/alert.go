@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AlertPriceAbove    = "price_above"
+	AlertPriceBelow    = "price_below"
+	AlertPercentChange = "percent_change"
+)
+
+type Alert struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          string     `json:"user_id"`
+	Stock           string     `json:"stock"`
+	Condition       string     `json:"condition"`
+	Threshold       float64    `json:"threshold"`
+	Active          bool       `json:"active"`
+	Armed           bool       `json:"armed"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type AlertEvent struct {
+	ID            int64     `json:"id"`
+	AlertID       uuid.UUID `json:"alert_id"`
+	Price         float64   `json:"price"`
+	ChangePercent float64   `json:"change_percent"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelAPNs    = "apns"
+	ChannelInApp   = "in_app"
+)
+
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryDead    = "dead"
+)
+
+type Notification struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    string         `json:"user_id"`
+	Kind      string         `json:"kind"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Data      map[string]any `json:"data"`
+	Read      bool           `json:"read"`
+	ReadAt    *time.Time     `json:"read_at"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserID  string `json:"-"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+	Target  string `json:"target"`
+}
+
+type NotificationDelivery struct {
+	ID            int64
+	UserID        string
+	Channel       string
+	Target        string
+	Kind          string
+	Title         string
+	Body          string
+	Data          map[string]any
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
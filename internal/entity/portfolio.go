@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CostMethodAverage = "average"
+	CostMethodFIFO    = "fifo"
+)
+
+const (
+	TransactionBuy      = "buy"
+	TransactionSell     = "sell"
+	TransactionDividend = "dividend"
+	TransactionSplit    = "split"
+)
+
+type Portfolio struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	CostMethod string    `json:"cost_method"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PortfolioTransaction is one ledger entry. Buys and sells use quantity, price
+// and fee, dividends use the cash amount and splits use the share ratio.
+type PortfolioTransaction struct {
+	ID          uuid.UUID       `json:"id"`
+	PortfolioID uuid.UUID       `json:"portfolio_id"`
+	Stock       string          `json:"stock"`
+	Type        string          `json:"type"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Price       decimal.Decimal `json:"price"`
+	Fee         decimal.Decimal `json:"fee"`
+	Amount      decimal.Decimal `json:"amount"`
+	Ratio       decimal.Decimal `json:"ratio"`
+	Note        string          `json:"note"`
+	TradedAt    time.Time       `json:"traded_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Holding is a position derived from the ledger. The quote fields are nil when
+// the position is closed or its quote could not be fetched.
+type Holding struct {
+	Stock         string           `json:"stock"`
+	Quantity      decimal.Decimal  `json:"quantity"`
+	AverageCost   decimal.Decimal  `json:"average_cost"`
+	CostBasis     decimal.Decimal  `json:"cost_basis"`
+	RealizedPnL   decimal.Decimal  `json:"realized_pnl"`
+	Dividends     decimal.Decimal  `json:"dividends"`
+	LastPrice     *decimal.Decimal `json:"last_price"`
+	MarketValue   *decimal.Decimal `json:"market_value"`
+	UnrealizedPnL *decimal.Decimal `json:"unrealized_pnl"`
+	QuoteError    string           `json:"quote_error,omitempty"`
+}
+
+type PortfolioSummary struct {
+	PortfolioID   uuid.UUID       `json:"portfolio_id"`
+	CostMethod    string          `json:"cost_method"`
+	OpenPositions int             `json:"open_positions"`
+	CostBasis     decimal.Decimal `json:"cost_basis"`
+	MarketValue   decimal.Decimal `json:"market_value"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	Dividends     decimal.Decimal `json:"dividends"`
+	TotalReturn   decimal.Decimal `json:"total_return"`
+	// Positions without a quote are left out of the market value and unrealized P&L
+	MissingQuotes int `json:"missing_quotes"`
+}
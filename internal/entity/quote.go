@@ -0,0 +1,9 @@
+package entity
+
+type Quote struct {
+	Stock         string  `json:"code"`
+	LastPrice     float64 `json:"last_price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"`
+}
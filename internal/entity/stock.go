@@ -0,0 +1,8 @@
+package entity
+
+// Stock is a listing as described by the stock service
+type Stock struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	Sector string `json:"sector"`
+}
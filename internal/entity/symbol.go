@@ -0,0 +1,32 @@
+package entity
+
+import "strings"
+
+// Exchange assumed for a bare code such as BBCA
+const DefaultExchange = "IDX"
+
+// Symbol identifies a listing by exchange and code, written as EXCHANGE:CODE
+// (for example IDX:BBCA or NASDAQ:AAPL)
+type Symbol struct {
+	Exchange string `json:"exchange"`
+	Code     string `json:"code"`
+}
+
+func (symbol Symbol) String() string {
+	return symbol.Exchange + ":" + symbol.Code
+}
+
+// ParseSymbol splits EXCHANGE:CODE into its parts. It does not check the code
+// against the exchange's format.
+func ParseSymbol(raw string) (Symbol, bool) {
+	raw = strings.ToUpper(strings.TrimSpace(raw))
+	exchange, code, found := strings.Cut(raw, ":")
+	if !found {
+		exchange, code = DefaultExchange, raw
+	}
+
+	if exchange == "" || code == "" {
+		return Symbol{}, false
+	}
+	return Symbol{Exchange: exchange, Code: code}, true
+}
@@ -1,6 +1,7 @@
 package entity
 
 type Underwriter struct {
-	ID   string `json:"underwriter_id"`
-	Name string `json:"name"`
+	ID       string         `json:"underwriter_id"`
+	Name     string         `json:"name"`
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -13,6 +13,7 @@ type User struct {
 	Password  string    `json:"password"`
 	Role      string    `json:"role"`
 	Verified  bool      `json:"verified"`
+	Locale    string    `json:"locale"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
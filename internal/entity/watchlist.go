@@ -1,10 +1,40 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	WatchlistSortAddedAt     = "added_at"
+	WatchlistSortAddedAtDesc = "-added_at"
+	WatchlistSortStock       = "stock"
+	WatchlistSortStockDesc   = "-stock"
 )
 
 type Watchlist struct {
-	UserId uuid.UUID `json:"user_id" validate:"required,uuid"`
-	Stock  string    `json:"stock" validate:"required"`
+	UserId     uuid.UUID        `json:"-"`
+	Stock      string           `json:"stock" validate:"required"`
+	AddedAt    time.Time        `json:"added_at"`
+	Note       string           `json:"note"`
+	TargetBuy  *decimal.Decimal `json:"target_buy"`
+	TargetSell *decimal.Decimal `json:"target_sell"`
+	Tags       []string         `json:"tags"`
+}
+
+// WatchlistFilter narrows and orders the entries returned for a user
+type WatchlistFilter struct {
+	Tag  string
+	Sort string
+}
+
+// WatchlistUpdate holds the fields of a partial update. Nil fields are left
+// unchanged and a zero target price clears the target.
+type WatchlistUpdate struct {
+	Note       *string
+	TargetBuy  *decimal.Decimal
+	TargetSell *decimal.Decimal
+	Tags       []string
 }
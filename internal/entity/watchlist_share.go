@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// WatchlistShare publishes a user's watchlist, or the entries with one tag,
+// under an unguessable slug
+type WatchlistShare struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       string     `json:"-"`
+	Slug         string     `json:"slug"`
+	Title        string     `json:"title"`
+	Tag          string     `json:"tag"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// PublicWatchlistEntry is the part of a watchlist entry shown on a public link
+type PublicWatchlistEntry struct {
+	Stock      string           `json:"stock"`
+	AddedAt    time.Time        `json:"added_at"`
+	Note       string           `json:"note"`
+	TargetBuy  *decimal.Decimal `json:"target_buy"`
+	TargetSell *decimal.Decimal `json:"target_sell"`
+	Tags       []string         `json:"tags"`
+}
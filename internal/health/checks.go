@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client"
+)
+
+// Pinger is implemented by the email transports that talk to a remote server
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+func PostgresCheck(db *sql.DB) Check {
+	return Check{
+		Name:     "postgres",
+		Critical: true,
+		Ping:     db.PingContext,
+	}
+}
+
+// RedisCheck is optional, the service falls back to Postgres while Redis is down
+func RedisCheck(redisCache *cache.Cache) Check {
+	return Check{
+		Name: "redis",
+		Ping: redisCache.Ping,
+	}
+}
+
+// SMTPCheck is optional, emails wait in the outbox until the server is back
+func SMTPCheck(pinger Pinger) Check {
+	return Check{
+		Name: "smtp",
+		Ping: pinger.Ping,
+	}
+}
+
+// StockServiceCheck is optional, the circuit breakers already handle an
+// unavailable Stock Backend. Any answer below 500 from every configured
+// service counts as reachable.
+func StockServiceCheck(httpClient *http.Client, routes client.StockRoutes) Check {
+	baseUrls := make([]string, 0, len(routes))
+	seen := map[string]bool{}
+	for _, baseUrl := range routes {
+		if baseUrl != "" && !seen[baseUrl] {
+			seen[baseUrl] = true
+			baseUrls = append(baseUrls, baseUrl)
+		}
+	}
+	sort.Strings(baseUrls)
+
+	return Check{
+		Name: "stock_service",
+		Ping: func(ctx context.Context) error {
+			if len(baseUrls) == 0 {
+				return fmt.Errorf("no stock service configured")
+			}
+
+			for _, baseUrl := range baseUrls {
+				if err := pingHTTP(ctx, httpClient, baseUrl); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func pingHTTP(ctx context.Context, httpClient *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%s answered %d", url, res.StatusCode)
+	}
+	return nil
+}
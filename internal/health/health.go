@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check pings one dependency. A failing critical check makes the service not
+// ready, a failing optional check is only reported because the service keeps
+// working without that dependency.
+type Check struct {
+	Name     string
+	Critical bool
+	Ping     func(ctx context.Context) error
+}
+
+type Result struct {
+	Name      string
+	Critical  bool
+	Status    string
+	Latency   time.Duration
+	Error     string
+	CheckedAt time.Time
+}
+
+// Checker runs every check in parallel, each within its own timeout, and
+// caches the results so frequent probes do not hammer the dependencies
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	results []Result
+	expires time.Time
+	group   singleflight.Group
+}
+
+func NewChecker(timeout time.Duration, ttl time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+		ttl:     ttl,
+	}
+}
+
+// Run returns the results of the last round of checks, or runs a new round when they are older than the TTL
+func (checker *Checker) Run(ctx context.Context) []Result {
+	checker.mu.Lock()
+	if time.Now().Before(checker.expires) {
+		results := checker.results
+		checker.mu.Unlock()
+		return results
+	}
+	checker.mu.Unlock()
+
+	// Concurrent probes share one round of checks
+	result := checker.group.DoChan("checks", func() (any, error) {
+		// The round is not tied to the probe that started it
+		results := checker.runChecks(context.WithoutCancel(ctx))
+
+		checker.mu.Lock()
+		checker.results = results
+		checker.expires = time.Now().Add(checker.ttl)
+		checker.mu.Unlock()
+		return results, nil
+	})
+
+	select {
+	case res := <-result:
+		return res.Val.([]Result)
+	case <-ctx.Done():
+		return checker.timedOut(ctx)
+	}
+}
+
+func (checker *Checker) runChecks(ctx context.Context) []Result {
+	results := make([]Result, len(checker.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checker.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = checker.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (checker *Checker) runCheck(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Ping(ctx)
+	result := Result{
+		Name:      check.Name,
+		Critical:  check.Critical,
+		Status:    StatusUp,
+		Latency:   time.Since(start),
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// timedOut reports every check as down when the caller gives up before the round finishes
+func (checker *Checker) timedOut(ctx context.Context) []Result {
+	now := time.Now()
+	results := make([]Result, 0, len(checker.checks))
+	for _, check := range checker.checks {
+		results = append(results, Result{
+			Name:      check.Name,
+			Critical:  check.Critical,
+			Status:    StatusDown,
+			Error:     ctx.Err().Error(),
+			CheckedAt: now,
+		})
+	}
+	return results
+}
+
+// Ready reports whether every critical check is up
+func Ready(results []Result) bool {
+	for _, result := range results {
+		if result.Critical && result.Status != StatusUp {
+			return false
+		}
+	}
+	return true
+}
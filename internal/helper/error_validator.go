@@ -27,6 +27,18 @@ func ValidationError(err error) string {
 		case "max":
 			return field + " must be less than or equal to " + fe.Param()
 
+		case "gt":
+			return field + " must be greater than " + fe.Param()
+
+		case "gte":
+			return field + " must be greater than or equal to " + fe.Param()
+
+		case "oneof":
+			return field + " must be one of " + fe.Param()
+
+		case "symbol":
+			return field + " must be a symbol such as IDX:BBCA"
+
 		default:
 			return field + " is invalid"
 		}
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDFormat bounds the IDs accepted from callers, which end up in logs and outgoing headers
+var requestIDFormat = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type requestIDKey struct{}
+
+// RequestIDKey is the key the request ID is stored under, both in the request
+// locals and in contexts derived from them
+var RequestIDKey = requestIDKey{}
+
+// WithRequestID returns a context that carries the request ID
+func WithRequestID(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, requestId)
+}
+
+// GetRequestID returns the request ID carried by ctx, if any
+func GetRequestID(ctx context.Context) string {
+	requestId, _ := ctx.Value(RequestIDKey).(string)
+	return requestId
+}
+
+// ValidRequestID reports whether an ID sent by a caller can be kept: a UUID,
+// or at most 64 letters, digits, dots, underscores and dashes
+func ValidRequestID(requestId string) bool {
+	return uuid.Validate(requestId) == nil || requestIDFormat.MatchString(requestId)
+}
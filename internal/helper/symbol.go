@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"fmt"
+	"regexp"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// symbolFormats is the code format of each supported exchange, set from the
+// configuration by RegisterSymbolValidation at startup
+var symbolFormats map[string]*regexp.Regexp
+
+// NormalizeSymbol parses a symbol, defaulting bare codes to IDX, and checks the
+// code against the format of its exchange
+func NormalizeSymbol(raw string) (entity.Symbol, error) {
+	symbol, ok := entity.ParseSymbol(raw)
+	if !ok {
+		return entity.Symbol{}, domainerr.ErrInvalidSymbol
+	}
+
+	format, ok := symbolFormats[symbol.Exchange]
+	if !ok {
+		return entity.Symbol{}, domainerr.ErrUnsupportedExchange
+	}
+
+	if !format.MatchString(symbol.Code) {
+		return entity.Symbol{}, domainerr.ErrInvalidSymbol
+	}
+	return symbol, nil
+}
+
+// RegisterSymbolValidation adds the "symbol" tag to the validator and checks
+// symbols against formats, which maps each supported exchange to the pattern
+// of its codes. It fails when a pattern does not compile, so the service does
+// not start without the exchanges it was configured for.
+func RegisterSymbolValidation(v *validator.Validate, formats map[string]string) error {
+	compiled := make(map[string]*regexp.Regexp, len(formats))
+	for exchange, pattern := range formats {
+		format, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid symbol format of %s: %w", exchange, err)
+		}
+		compiled[strings.ToUpper(exchange)] = format
+	}
+	symbolFormats = compiled
+
+	return v.RegisterValidation("symbol", func(fl validator.FieldLevel) bool {
+		_, err := NormalizeSymbol(fl.Field().String())
+		return err == nil
+	})
+}
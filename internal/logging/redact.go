@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Attributes whose key contains one of these are never logged
+var sensitiveKeys = []string{"password", "token", "secret", "authorization", "cookie", "api_key", "apikey"}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+\S+`)
+)
+
+// Redact hides the mailbox of email addresses and removes bearer tokens and JWTs from s
+func Redact(s string) string {
+	if strings.Contains(s, "@") {
+		s = emailPattern.ReplaceAllString(s, "***@$1")
+	}
+	if strings.Contains(s, "eyJ") {
+		s = jwtPattern.ReplaceAllString(s, redacted)
+	}
+	return bearerPattern.ReplaceAllString(s, "$1 "+redacted)
+}
+
+// redactAttr runs on every attribute, the message included, before it is written
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, Redact(value.Error()))
+		case fmt.Stringer:
+			return slog.String(attr.Key, Redact(value.String()))
+		}
+		if isComposite(attr.Value.Any()) {
+			return slog.Any(attr.Key, redactComposite(attr.Value.Any()))
+		}
+	}
+	return attr
+}
+
+func isComposite(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array, reflect.Pointer:
+		return true
+	}
+	return false
+}
+
+// redactComposite logs maps, structs and slices as their JSON form with the
+// sensitive fields removed and every string redacted. A value that cannot be
+// encoded is logged as its redacted fmt form.
+func redactComposite(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return Redact(fmt.Sprint(value))
+	}
+
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return Redact(fmt.Sprint(value))
+	}
+	return redactValue(decoded)
+}
+
+func redactValue(value any) any {
+	switch value := value.(type) {
+	case string:
+		return Redact(value)
+	case map[string]any:
+		for key, field := range value {
+			if isSensitiveKey(key) {
+				value[key] = redacted
+			} else {
+				value[key] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
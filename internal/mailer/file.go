@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileSender writes every message as an .eml file for local development
+type FileSender struct {
+	dir  string
+	from mail.Address
+}
+
+func NewFileSender(dir string, from mail.Address) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileSender{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (sender *FileSender) Send(ctx context.Context, message *Message) error {
+	raw, err := withDefaultFrom(message, sender.from).Bytes()
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(sender.dir, name), raw, 0o644)
+}
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"net/mail"
+	"sync"
+)
+
+// MemorySender keeps every message in memory so tests can assert on what was sent
+type MemorySender struct {
+	from mail.Address
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySender(from mail.Address) *MemorySender {
+	return &MemorySender{
+		from: from,
+	}
+}
+
+func (sender *MemorySender) Send(ctx context.Context, message *Message) error {
+	if message.To.Address == "" {
+		return ErrMissingRecipient
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.messages = append(sender.messages, *withDefaultFrom(message, sender.from))
+	return nil
+}
+
+// Messages returns a copy of the captured messages in the order they were sent
+func (sender *MemorySender) Messages() []Message {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	return append([]Message(nil), sender.messages...)
+}
+
+// LastTo returns the most recent message sent to the address
+func (sender *MemorySender) LastTo(address string) (Message, bool) {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	for i := len(sender.messages) - 1; i >= 0; i-- {
+		if sender.messages[i].To.Address == address {
+			return sender.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+func (sender *MemorySender) Reset() {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.messages = nil
+}
@@ -0,0 +1,113 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrMissingRecipient = errors.New("email recipient is required")
+
+// Message is an email with a plain text and an HTML alternative
+type Message struct {
+	From    mail.Address
+	To      mail.Address
+	Subject string
+	Text    string
+	HTML    string
+
+	// ListUnsubscribe is a mailto: or https: URL, only set on non-transactional mail
+	ListUnsubscribe string
+}
+
+// Bytes renders the message as RFC 5322 with a multipart/alternative body.
+// Non-ASCII header values are RFC 2047 encoded and bodies are quoted-printable.
+func (message *Message) Bytes() ([]byte, error) {
+	if message.To.Address == "" {
+		return nil, ErrMissingRecipient
+	}
+
+	messageId, err := newMessageID(message.From.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + message.From.String(),
+		"To: " + message.To.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageId,
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=\"" + body.Boundary() + "\"",
+	}
+	if message.ListUnsubscribe != "" {
+		headers = append(headers,
+			"List-Unsubscribe: <"+message.ListUnsubscribe+">",
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(strings.Join(headers, "\r\n"))
+	out.WriteString("\r\n\r\n")
+
+	// Clients show the last alternative they support, so HTML goes last
+	if err := writePart(body, "text/plain; charset=UTF-8", message.Text); err != nil {
+		return nil, err
+	}
+
+	if message.HTML != "" {
+		if err := writePart(body, "text/html; charset=UTF-8", message.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func writePart(writer *multipart.Writer, contentType string, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func newMessageID(from string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at != -1 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain), nil
+}
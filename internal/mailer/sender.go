@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"stock_backend/config"
+)
+
+const (
+	TransportSMTP   = "smtp"
+	TransportFile   = "file"
+	TransportMemory = "memory"
+)
+
+// EmailSender delivers a message through a transport
+type EmailSender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewSender builds the sender selected by the transport of cfg (smtp, file or memory)
+func NewSender(cfg config.Email) (EmailSender, error) {
+	from := mail.Address{
+		Name:    cfg.FromName,
+		Address: cfg.From,
+	}
+	if from.Address == "" {
+		from.Address = cfg.SMTP.Email
+	}
+
+	switch cfg.Transport {
+	case TransportSMTP:
+		return NewSMTPSender(SMTPConfig{
+			Host:        cfg.SMTP.Host,
+			Port:        cfg.SMTP.Port,
+			Username:    cfg.SMTP.Email,
+			Password:    cfg.SMTP.Password,
+			TLSMode:     cfg.SMTP.TLSMode,
+			DialTimeout: cfg.SMTP.DialTimeout,
+			PoolSize:    cfg.SMTP.PoolSize,
+			From:        from,
+		})
+
+	case TransportFile:
+		return NewFileSender(cfg.FileDir, from)
+
+	case TransportMemory:
+		return NewMemorySender(from), nil
+
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_TRANSPORT %q", cfg.Transport)
+	}
+}
+
+// withDefaultFrom returns a copy of the message with the sender's address filled in
+func withDefaultFrom(message *Message, from mail.Address) *Message {
+	copied := *message
+	if copied.From.Address == "" {
+		copied.From = from
+	}
+	return &copied
+}
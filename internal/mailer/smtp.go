@@ -0,0 +1,242 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+const (
+	TLSModeNone     = "none"
+	TLSModeStartTLS = "starttls"
+	TLSModeImplicit = "implicit"
+)
+
+// Applied to every SMTP exchange when the caller's context has no deadline
+const defaultSMTPCommandTimeout = 30 * time.Second
+
+type SMTPConfig struct {
+	Host        string
+	Port        string
+	Username    string
+	Password    string
+	TLSMode     string
+	DialTimeout time.Duration
+	PoolSize    int
+	From        mail.Address
+}
+
+// SMTPSender keeps up to PoolSize idle connections open and reuses them between messages
+type SMTPSender struct {
+	cfg  SMTPConfig
+	idle chan *smtpConn
+}
+
+type smtpConn struct {
+	conn   net.Conn
+	client *smtp.Client
+}
+
+func NewSMTPSender(cfg SMTPConfig) (*SMTPSender, error) {
+	if cfg.Host == "" || cfg.Port == "" || cfg.From.Address == "" {
+		return nil, errors.New("missing smtp configuration")
+	}
+
+	switch cfg.TLSMode {
+	case TLSModeNone, TLSModeStartTLS, TLSModeImplicit:
+	default:
+		return nil, fmt.Errorf("unknown SMTP_TLS_MODE %q", cfg.TLSMode)
+	}
+
+	if cfg.PoolSize < 1 {
+		cfg.PoolSize = 1
+	}
+
+	return &SMTPSender{
+		cfg:  cfg,
+		idle: make(chan *smtpConn, cfg.PoolSize),
+	}, nil
+}
+
+func (sender *SMTPSender) Send(ctx context.Context, message *Message) error {
+	message = withDefaultFrom(message, sender.cfg.From)
+	raw, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sender.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := sender.deliver(ctx, conn, message, raw); err != nil {
+		// The connection state is unknown after a failed exchange
+		_ = conn.client.Close()
+		return err
+	}
+
+	sender.release(conn)
+	return nil
+}
+
+// Ping checks that the SMTP server accepts a connection and answers NOOP. The
+// connection is kept for the next message.
+func (sender *SMTPSender) Ping(ctx context.Context) error {
+	conn, err := sender.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.conn.SetDeadline(deadline)
+	}
+
+	if err := conn.client.Noop(); err != nil {
+		_ = conn.client.Close()
+		return err
+	}
+
+	sender.release(conn)
+	return nil
+}
+
+// Close sends QUIT on every idle connection
+func (sender *SMTPSender) Close() error {
+	for {
+		select {
+		case conn := <-sender.idle:
+			_ = conn.client.Quit()
+		default:
+			return nil
+		}
+	}
+}
+
+func (sender *SMTPSender) deliver(ctx context.Context, conn *smtpConn, message *Message, raw []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSMTPCommandTimeout)
+	}
+
+	if err := conn.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// Unblock the exchange as soon as the caller gives up
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if err := conn.client.Mail(message.From.Address); err != nil {
+		return err
+	}
+
+	if err := conn.client.Rcpt(message.To.Address); err != nil {
+		return err
+	}
+
+	writer, err := conn.client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(raw); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// acquire returns a healthy idle connection or dials a new one
+func (sender *SMTPSender) acquire(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case conn := <-sender.idle:
+			_ = conn.conn.SetDeadline(time.Now().Add(sender.dialTimeout()))
+			if err := conn.client.Reset(); err == nil {
+				return conn, nil
+			}
+			_ = conn.client.Close()
+		default:
+			return sender.dial(ctx)
+		}
+	}
+}
+
+func (sender *SMTPSender) release(conn *smtpConn) {
+	_ = conn.conn.SetDeadline(time.Time{})
+
+	select {
+	case sender.idle <- conn:
+	default:
+		_ = conn.client.Quit()
+	}
+}
+
+func (sender *SMTPSender) dial(ctx context.Context) (*smtpConn, error) {
+	address := net.JoinHostPort(sender.cfg.Host, sender.cfg.Port)
+	tlsConfig := &tls.Config{ServerName: sender.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	dialer := &net.Dialer{Timeout: sender.dialTimeout()}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the greeting, TLS handshake and authentication
+	if err := conn.SetDeadline(time.Now().Add(sender.dialTimeout())); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if sender.cfg.TLSMode == TLSModeImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, sender.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if sender.cfg.TLSMode == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			_ = client.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+
+		if err := client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	if sender.cfg.Username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted connection to a remote host
+		auth := smtp.PlainAuth("", sender.cfg.Username, sender.cfg.Password, sender.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	return &smtpConn{conn: conn, client: client}, nil
+}
+
+func (sender *SMTPSender) dialTimeout() time.Duration {
+	if sender.cfg.DialTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return sender.cfg.DialTimeout
+}
@@ -0,0 +1,9 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrAlertNotFound            = errors.New("alert not found")
+	ErrAlertIdInvalid           = errors.New("invalid alert id")
+	ErrAlertStockNotInWatchlist = errors.New("stock is not in watchlist")
+)
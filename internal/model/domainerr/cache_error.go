@@ -0,0 +1,7 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrCacheUnavailable = errors.New("cache is unavailable")
+)
@@ -0,0 +1,8 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrCircuitBreakerNotFound = errors.New("circuit breaker not found")
+	ErrCircuitOverrideInvalid = errors.New("override must be one of open, closed or none")
+)
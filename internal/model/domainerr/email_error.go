@@ -0,0 +1,10 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrEmailNotFound      = errors.New("email not found")
+	ErrEmailIdInvalid     = errors.New("invalid email id")
+	ErrEmailNotRetryable  = errors.New("only failed emails can be retried")
+	ErrEmailStatusInvalid = errors.New("invalid email status")
+)
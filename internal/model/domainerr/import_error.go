@@ -0,0 +1,12 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrImportEmpty         = errors.New("import file has no rows")
+	ErrImportTooLarge      = errors.New("import file has too many rows")
+	ErrImportInvalidFormat = errors.New("import file is not valid csv or json")
+	ErrExportFormatInvalid = errors.New("export format must be csv or json")
+	ErrImportDuplicateRow  = errors.New("duplicate row in import file")
+	ErrImportInvalidCode   = errors.New("invalid code")
+)
@@ -0,0 +1,12 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrNotificationNotFound       = errors.New("notification not found")
+	ErrNotificationIdInvalid      = errors.New("invalid notification id")
+	ErrNotificationChannelInvalid = errors.New("invalid notification channel")
+	ErrNotificationTargetRequired = errors.New("notification target is required")
+	ErrNotificationTargetInvalid  = errors.New("invalid notification target")
+	ErrNotificationEmailNotOwned  = errors.New("notification email must be the account email")
+)
@@ -0,0 +1,18 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrPortfolioNotFound             = errors.New("portfolio not found")
+	ErrPortfolioIdInvalid            = errors.New("invalid portfolio id")
+	ErrPortfolioDuplicate            = errors.New("portfolio name already exists")
+	ErrPortfolioTransactionNotFound  = errors.New("transaction not found")
+	ErrPortfolioTransactionIdInvalid = errors.New("invalid transaction id")
+	ErrPortfolioQuantityInvalid      = errors.New("quantity must be greater than 0")
+	ErrPortfolioPriceInvalid         = errors.New("price must be greater than 0")
+	ErrPortfolioFeeInvalid           = errors.New("fee must not be negative")
+	ErrPortfolioAmountInvalid        = errors.New("amount must be greater than 0")
+	ErrPortfolioRatioInvalid         = errors.New("ratio must be greater than 0 and not 1")
+	ErrPortfolioTradedAtInvalid      = errors.New("traded_at must not be in the future")
+	ErrPortfolioInsufficientShares   = errors.New("not enough shares to sell")
+)
@@ -0,0 +1,8 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrInvalidSymbol       = errors.New("invalid stock symbol")
+	ErrUnsupportedExchange = errors.New("exchange is not supported")
+)
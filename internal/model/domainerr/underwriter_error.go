@@ -0,0 +1,7 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrUnderwriterNotFound = errors.New("underwriter not found")
+)
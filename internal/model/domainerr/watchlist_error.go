@@ -6,4 +6,9 @@ var (
 	ErrWatchlistNotFound       = errors.New("watchlist not found")
 	ErrWatchlistDuplicate      = errors.New("duplicate stock in watchlist")
 	ErrStockServiceUnavailable = errors.New("stock service is unavailable")
+	ErrQuoteUnavailable        = errors.New("quote unavailable")
+	ErrInvalidInclude          = errors.New("invalid include parameter")
+	ErrWatchlistSortInvalid    = errors.New("sort must be one of added_at -added_at stock -stock")
+	ErrWatchlistTargetInvalid  = errors.New("target buy must be lower than target sell")
+	ErrWatchlistTargetNegative = errors.New("target prices must not be negative")
 )
@@ -0,0 +1,9 @@
+package domainerr
+
+import "errors"
+
+var (
+	ErrWatchlistShareNotFound      = errors.New("shared watchlist not found")
+	ErrWatchlistShareIdInvalid     = errors.New("invalid share id")
+	ErrWatchlistShareExpiryInvalid = errors.New("expires_at must be in the future")
+)
@@ -0,0 +1,12 @@
+package request
+
+type CreateAlertRequest struct {
+	Stock     string  `json:"stock" validate:"required,symbol"`
+	Condition string  `json:"condition" validate:"required,oneof=price_above price_below percent_change"`
+	Threshold float64 `json:"threshold" validate:"required,gt=0"`
+}
+
+type UpdateAlertRequest struct {
+	Threshold *float64 `json:"threshold" validate:"omitempty,gt=0"`
+	Active    *bool    `json:"active"`
+}
@@ -0,0 +1,9 @@
+package request
+
+type BatchWatchlistRequest struct {
+	Stocks []string `json:"stocks" validate:"required,min=1,max=100"`
+}
+
+type BatchFavoriteRequest struct {
+	UnderwriterIds []string `json:"underwriter_ids" validate:"required,min=1,max=100"`
+}
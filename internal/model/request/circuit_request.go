@@ -0,0 +1,5 @@
+package request
+
+type SetCircuitOverrideRequest struct {
+	Override string `json:"override"`
+}
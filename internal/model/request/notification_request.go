@@ -0,0 +1,6 @@
+package request
+
+type UpdateNotificationPreferenceRequest struct {
+	Enabled *bool  `json:"enabled" validate:"required"`
+	Target  string `json:"target" validate:"max=512"`
+}
@@ -0,0 +1,31 @@
+package request
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CreatePortfolioRequest struct {
+	Name       string `json:"name" validate:"required,max=64"`
+	CostMethod string `json:"cost_method" validate:"omitempty,oneof=average fifo"`
+}
+
+type UpdatePortfolioRequest struct {
+	Name       *string `json:"name" validate:"omitempty,min=1,max=64"`
+	CostMethod *string `json:"cost_method" validate:"omitempty,oneof=average fifo"`
+}
+
+// CreateTransactionRequest carries decimal amounts, which accept both JSON
+// numbers and strings. TradedAt defaults to the time of the request.
+type CreateTransactionRequest struct {
+	Stock    string          `json:"stock" validate:"required,symbol"`
+	Type     string          `json:"type" validate:"required,oneof=buy sell dividend split"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Price    decimal.Decimal `json:"price"`
+	Fee      decimal.Decimal `json:"fee"`
+	Amount   decimal.Decimal `json:"amount"`
+	Ratio    decimal.Decimal `json:"ratio"`
+	Note     string          `json:"note" validate:"max=500"`
+	TradedAt *time.Time      `json:"traded_at"`
+}
@@ -1,5 +1,24 @@
 package request
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type AddWatchlistRequest struct {
-	Stock string `json:"stock" validate:"required,len=4"`
+	Stock string `json:"stock" validate:"required,symbol"`
+}
+
+type UpdateWatchlistRequest struct {
+	Note       *string          `json:"note" validate:"omitempty,max=500"`
+	TargetBuy  *decimal.Decimal `json:"target_buy"`
+	TargetSell *decimal.Decimal `json:"target_sell"`
+	Tags       []string         `json:"tags" validate:"omitempty,max=10,dive,min=1,max=32"`
+}
+
+type CreateWatchlistShareRequest struct {
+	Title     string     `json:"title" validate:"max=100"`
+	Tag       string     `json:"tag" validate:"max=32"`
+	ExpiresAt *time.Time `json:"expires_at"`
 }
@@ -0,0 +1,27 @@
+package response
+
+import "stock_backend/internal/entity"
+
+type CreateAlertResponse struct {
+	Message string       `json:"message"`
+	Data    entity.Alert `json:"data"`
+}
+
+type GetAlertsResponse struct {
+	Message string         `json:"message"`
+	Data    []entity.Alert `json:"data"`
+}
+
+type UpdateAlertResponse struct {
+	Message string       `json:"message"`
+	Data    entity.Alert `json:"data"`
+}
+
+type DeleteAlertResponse struct {
+	Message string `json:"message"`
+}
+
+type GetAlertEventsResponse struct {
+	Message string              `json:"message"`
+	Data    []entity.AlertEvent `json:"data"`
+}
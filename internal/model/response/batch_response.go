@@ -0,0 +1,20 @@
+package response
+
+const (
+	BatchItemAdded   = "added"
+	BatchItemRemoved = "removed"
+	BatchItemFailed  = "failed"
+)
+
+type BatchItemResult struct {
+	Code   string `json:"code"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Message   string            `json:"message"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
@@ -0,0 +1,20 @@
+package response
+
+type CacheStatus struct {
+	Degraded             bool `json:"degraded"`
+	PendingInvalidations int  `json:"pending_invalidations"`
+}
+
+type GetCacheStatusResponse struct {
+	Message string      `json:"message"`
+	Data    CacheStatus `json:"data"`
+}
+
+type PurgedStockCache struct {
+	Purged int64 `json:"purged"`
+}
+
+type PurgeStockCacheResponse struct {
+	Message string           `json:"message"`
+	Data    PurgedStockCache `json:"data"`
+}
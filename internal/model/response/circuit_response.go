@@ -0,0 +1,35 @@
+package response
+
+type CircuitCounts struct {
+	Requests             uint32 `json:"requests"`
+	TotalSuccesses       uint32 `json:"total_successes"`
+	TotalFailures        uint32 `json:"total_failures"`
+	ConsecutiveSuccesses uint32 `json:"consecutive_successes"`
+	ConsecutiveFailures  uint32 `json:"consecutive_failures"`
+}
+
+type CircuitSettings struct {
+	MaxRequests  uint32  `json:"max_requests"`
+	Interval     string  `json:"interval"`
+	Timeout      string  `json:"timeout"`
+	MinRequests  uint32  `json:"min_requests"`
+	FailureRatio float64 `json:"failure_ratio"`
+}
+
+type CircuitBreaker struct {
+	Name     string          `json:"name"`
+	State    string          `json:"state"`
+	Override string          `json:"override"`
+	Counts   CircuitCounts   `json:"counts"`
+	Settings CircuitSettings `json:"settings"`
+}
+
+type GetCircuitBreakersResponse struct {
+	Message string           `json:"message"`
+	Data    []CircuitBreaker `json:"data"`
+}
+
+type SetCircuitOverrideResponse struct {
+	Message string         `json:"message"`
+	Data    CircuitBreaker `json:"data"`
+}
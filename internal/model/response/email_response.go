@@ -0,0 +1,12 @@
+package response
+
+import "stock_backend/internal/entity"
+
+type GetEmailsResponse struct {
+	Message string               `json:"message"`
+	Data    []entity.EmailOutbox `json:"data"`
+}
+
+type RetryEmailResponse struct {
+	Message string `json:"message"`
+}
@@ -1,5 +1,7 @@
 package response
 
+import "stock_backend/internal/entity"
+
 type AddFavoriteResponse struct {
 	Message string `json:"message"`
 }
@@ -9,6 +11,6 @@ type RemoveFavoriteResponse struct {
 }
 
 type GetFavoritesResponse struct {
-	Message string   `json:"message"`
-	Data    []string `json:"data"`
+	Message string               `json:"message"`
+	Data    []entity.Underwriter `json:"data"`
 }
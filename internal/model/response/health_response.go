@@ -0,0 +1,61 @@
+package response
+
+import "time"
+
+type HealthCheck struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+type LivenessResponse struct {
+	Message string `json:"message"`
+}
+
+type ReadinessResponse struct {
+	Message string    `json:"message"`
+	Data    Readiness `json:"data"`
+}
+
+type DatabasePoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     float64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64   `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64   `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed"`
+}
+
+type RedisPoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+type HealthDetails struct {
+	Ready           bool              `json:"ready"`
+	Checks          []HealthCheck     `json:"checks"`
+	CircuitBreakers []CircuitBreaker  `json:"circuit_breakers"`
+	Database        DatabasePoolStats `json:"database"`
+	Redis           RedisPoolStats    `json:"redis"`
+	Cache           CacheStatus       `json:"cache"`
+}
+
+type GetHealthDetailsResponse struct {
+	Message string        `json:"message"`
+	Data    HealthDetails `json:"data"`
+}
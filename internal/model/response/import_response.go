@@ -0,0 +1,20 @@
+package response
+
+const (
+	ImportRowImported = "imported"
+	ImportRowFailed   = "failed"
+)
+
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Code   string `json:"code"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Message  string            `json:"message"`
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	Rows     []ImportRowResult `json:"rows"`
+}
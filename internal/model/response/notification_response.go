@@ -0,0 +1,23 @@
+package response
+
+import "stock_backend/internal/entity"
+
+type GetNotificationsResponse struct {
+	Message string                `json:"message"`
+	Unread  int                   `json:"unread"`
+	Data    []entity.Notification `json:"data"`
+}
+
+type MarkNotificationResponse struct {
+	Message string `json:"message"`
+}
+
+type GetNotificationPreferencesResponse struct {
+	Message string                          `json:"message"`
+	Data    []entity.NotificationPreference `json:"data"`
+}
+
+type UpdateNotificationPreferenceResponse struct {
+	Message string                        `json:"message"`
+	Data    entity.NotificationPreference `json:"data"`
+}
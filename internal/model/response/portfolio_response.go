@@ -0,0 +1,51 @@
+package response
+
+import "stock_backend/internal/entity"
+
+type CreatePortfolioResponse struct {
+	Message string           `json:"message"`
+	Data    entity.Portfolio `json:"data"`
+}
+
+type GetPortfoliosResponse struct {
+	Message string             `json:"message"`
+	Data    []entity.Portfolio `json:"data"`
+}
+
+type GetPortfolioResponse struct {
+	Message string           `json:"message"`
+	Data    entity.Portfolio `json:"data"`
+}
+
+type UpdatePortfolioResponse struct {
+	Message string           `json:"message"`
+	Data    entity.Portfolio `json:"data"`
+}
+
+type DeletePortfolioResponse struct {
+	Message string `json:"message"`
+}
+
+type CreateTransactionResponse struct {
+	Message string                      `json:"message"`
+	Data    entity.PortfolioTransaction `json:"data"`
+}
+
+type GetTransactionsResponse struct {
+	Message string                        `json:"message"`
+	Data    []entity.PortfolioTransaction `json:"data"`
+}
+
+type DeleteTransactionResponse struct {
+	Message string `json:"message"`
+}
+
+type GetHoldingsResponse struct {
+	Message string           `json:"message"`
+	Data    []entity.Holding `json:"data"`
+}
+
+type GetPortfolioSummaryResponse struct {
+	Message string                  `json:"message"`
+	Data    entity.PortfolioSummary `json:"data"`
+}
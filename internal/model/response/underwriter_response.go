@@ -0,0 +1,13 @@
+package response
+
+import "stock_backend/internal/entity"
+
+type GetUnderwritersResponse struct {
+	Message string               `json:"message"`
+	Data    []entity.Underwriter `json:"data"`
+}
+
+type SyncUnderwritersResponse struct {
+	Message string `json:"message"`
+	Synced  int    `json:"synced"`
+}
@@ -1,5 +1,7 @@
 package response
 
+import "stock_backend/internal/entity"
+
 type RemoveWatchlistResponse struct {
 	Message string `json:"message"`
 }
@@ -9,6 +11,28 @@ type AddWatchlistResponse struct {
 }
 
 type GetWatchlistResponse struct {
-	Message string   `json:"message"`
-	Stocks  []string `json:"stocks"`
+	Message string             `json:"message"`
+	Stocks  []entity.Watchlist `json:"stocks"`
+}
+
+type UpdateWatchlistResponse struct {
+	Message string           `json:"message"`
+	Data    entity.Watchlist `json:"data"`
+}
+
+type StockQuote struct {
+	LastPrice float64 `json:"last_price"`
+	Change    float64 `json:"change"`
+	Volume    int64   `json:"volume"`
+}
+
+type WatchlistStockQuote struct {
+	Stock      string      `json:"stock"`
+	Quote      *StockQuote `json:"quote"`
+	QuoteError string      `json:"quote_error,omitempty"`
+}
+
+type GetWatchlistQuotesResponse struct {
+	Message string                `json:"message"`
+	Stocks  []WatchlistStockQuote `json:"stocks"`
 }
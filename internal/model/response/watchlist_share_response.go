@@ -0,0 +1,44 @@
+package response
+
+import (
+	"stock_backend/internal/entity"
+	"time"
+)
+
+// WatchlistShare is a share link as seen by its owner
+type WatchlistShare struct {
+	entity.WatchlistShare
+	Path string `json:"path"`
+}
+
+type CreateWatchlistShareResponse struct {
+	Message string         `json:"message"`
+	Data    WatchlistShare `json:"data"`
+}
+
+type GetWatchlistSharesResponse struct {
+	Message string           `json:"message"`
+	Data    []WatchlistShare `json:"data"`
+}
+
+type RotateWatchlistShareResponse struct {
+	Message string         `json:"message"`
+	Data    WatchlistShare `json:"data"`
+}
+
+type RevokeWatchlistShareResponse struct {
+	Message string `json:"message"`
+}
+
+type PublicWatchlist struct {
+	Title     string                        `json:"title"`
+	Tag       string                        `json:"tag"`
+	SharedAt  time.Time                     `json:"shared_at"`
+	ExpiresAt *time.Time                    `json:"expires_at"`
+	Stocks    []entity.PublicWatchlistEntry `json:"stocks"`
+}
+
+type GetPublicWatchlistResponse struct {
+	Message string          `json:"message"`
+	Data    PublicWatchlist `json:"data"`
+}
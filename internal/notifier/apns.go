@@ -0,0 +1,160 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"stock_backend/internal/entity"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Apple rejects provider tokens older than an hour, so refresh well before that
+const apnsTokenLifetime = 50 * time.Minute
+
+type apnsPayload struct {
+	Aps struct {
+		Alert struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		} `json:"alert"`
+		Sound string `json:"sound"`
+	} `json:"aps"`
+	Kind string         `json:"kind"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// APNsTokenSigner creates the ES256 provider token sent in the authorization header
+type APNsTokenSigner struct {
+	key    *ecdsa.PrivateKey
+	keyId  string
+	teamId string
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func NewAPNsTokenSigner(key *ecdsa.PrivateKey, keyId string, teamId string) *APNsTokenSigner {
+	return &APNsTokenSigner{
+		key:    key,
+		keyId:  keyId,
+		teamId: teamId,
+	}
+}
+
+// LoadAPNsTokenSigner reads the .p8 signing key downloaded from the Apple developer portal
+func LoadAPNsTokenSigner(keyFile string, keyId string, teamId string) (*APNsTokenSigner, error) {
+	pem, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAPNsTokenSigner(key, keyId, teamId), nil
+}
+
+func (signer *APNsTokenSigner) Token() (string, error) {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	if signer.token != "" && time.Since(signer.issuedAt) < apnsTokenLifetime {
+		return signer.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": signer.teamId,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = signer.keyId
+
+	signed, err := token.SignedString(signer.key)
+	if err != nil {
+		return "", err
+	}
+
+	signer.token = signed
+	signer.issuedAt = now
+	return signed, nil
+}
+
+// APNsChannel pushes the message to an iOS device. The target is the device token.
+// The base URL is configurable so tests can point it at a local HTTP/2 server.
+type APNsChannel struct {
+	httpClient *http.Client
+	baseUrl    string
+	topic      string
+	signer     *APNsTokenSigner
+}
+
+func NewAPNsChannel(httpClient *http.Client, baseUrl string, topic string, signer *APNsTokenSigner) Channel {
+	return &APNsChannel{
+		httpClient: httpClient,
+		baseUrl:    baseUrl,
+		topic:      topic,
+		signer:     signer,
+	}
+}
+
+func (channel *APNsChannel) Name() string {
+	return entity.ChannelAPNs
+}
+
+func (channel *APNsChannel) Send(ctx context.Context, target string, message Message) error {
+	var payload apnsPayload
+	payload.Aps.Alert.Title = message.Title
+	payload.Aps.Alert.Body = message.Body
+	payload.Aps.Sound = "default"
+	payload.Kind = message.Kind
+	payload.Data = message.Data
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/3/device/%s", channel.baseUrl, target)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apns-topic", channel.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	if channel.signer != nil {
+		token, err := channel.signer.Token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "bearer "+token)
+	}
+
+	res, err := channel.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var apnsError struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&apnsError)
+		return fmt.Errorf("apns responded with status %d: %s", res.StatusCode, apnsError.Reason)
+	}
+	return nil
+}
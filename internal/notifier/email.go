@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+	"html"
+	"net/mail"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/mailer"
+)
+
+type EmailChannel struct {
+	sender         mailer.EmailSender
+	unsubscribeUrl string
+}
+
+func NewEmailChannel(sender mailer.EmailSender, unsubscribeUrl string) Channel {
+	return &EmailChannel{
+		sender:         sender,
+		unsubscribeUrl: unsubscribeUrl,
+	}
+}
+
+func (channel *EmailChannel) Name() string {
+	return entity.ChannelEmail
+}
+
+func (channel *EmailChannel) Send(ctx context.Context, target string, message Message) error {
+	return channel.sender.Send(ctx, &mailer.Message{
+		To:              mail.Address{Address: target},
+		Subject:         message.Title,
+		Text:            message.Body,
+		HTML:            "<p>" + html.EscapeString(message.Body) + "</p>",
+		ListUnsubscribe: channel.unsubscribeUrl,
+	})
+}
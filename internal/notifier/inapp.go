@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"context"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// InAppChannel stores the message in the user's inbox. The target is the user id.
+type InAppChannel struct {
+	repository repository.NotificationRepository
+}
+
+func NewInAppChannel(repository repository.NotificationRepository) Channel {
+	return &InAppChannel{
+		repository: repository,
+	}
+}
+
+func (channel *InAppChannel) Name() string {
+	return entity.ChannelInApp
+}
+
+func (channel *InAppChannel) Send(ctx context.Context, target string, message Message) error {
+	notification := &entity.Notification{
+		ID:     uuid.New(),
+		UserID: target,
+		Kind:   message.Kind,
+		Title:  message.Title,
+		Body:   message.Body,
+		Data:   message.Data,
+	}
+
+	if notification.Data == nil {
+		notification.Data = map[string]any{}
+	}
+	return channel.repository.CreateNotification(ctx, notification)
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/repository"
+)
+
+// Message is the channel independent content of a notification
+type Message struct {
+	Kind  string
+	Title string
+	Body  string
+	Data  map[string]any
+}
+
+// Channel delivers a message to a single target such as an email address, webhook URL or device token
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, target string, message Message) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, userId string, message Message) error
+	// Deliveries resolves the deliveries of a message without queueing them,
+	// for callers that queue them in their own transaction
+	Deliveries(ctx context.Context, userId string, message Message) ([]entity.NotificationDelivery, error)
+}
+
+// QueueNotifier resolves the user's channel preferences and queues one delivery per
+// enabled channel. The deliveries are sent later by the notification worker.
+type QueueNotifier struct {
+	notificationRepository repository.NotificationRepository
+	deliveryRepository     repository.NotificationDeliveryRepository
+}
+
+func NewQueueNotifier(notificationRepository repository.NotificationRepository, deliveryRepository repository.NotificationDeliveryRepository) Notifier {
+	return &QueueNotifier{
+		notificationRepository: notificationRepository,
+		deliveryRepository:     deliveryRepository,
+	}
+}
+
+func (notifier *QueueNotifier) Notify(ctx context.Context, userId string, message Message) error {
+	deliveries, err := notifier.Deliveries(ctx, userId, message)
+	if err != nil {
+		return err
+	}
+
+	return notifier.deliveryRepository.EnqueueDeliveries(ctx, deliveries)
+}
+
+// Deliveries returns one delivery per channel the user enabled
+func (notifier *QueueNotifier) Deliveries(ctx context.Context, userId string, message Message) ([]entity.NotificationDelivery, error) {
+	preferences, err := notifier.notificationRepository.GetPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The in-app inbox is on unless the user turned it off
+	enabled := map[string]string{entity.ChannelInApp: userId}
+	for _, preference := range preferences {
+		if !preference.Enabled {
+			delete(enabled, preference.Channel)
+			continue
+		}
+
+		switch preference.Channel {
+		case entity.ChannelInApp:
+			enabled[preference.Channel] = userId
+
+		case entity.ChannelEmail:
+			// Always the account address, which is the only email target a user can save
+			target, err := notifier.notificationRepository.GetUserEmail(ctx, userId)
+			if err != nil {
+				return nil, err
+			}
+			enabled[preference.Channel] = target
+
+		default:
+			if preference.Target != "" {
+				enabled[preference.Channel] = preference.Target
+			}
+		}
+	}
+
+	deliveries := make([]entity.NotificationDelivery, 0, len(enabled))
+	for channel, target := range enabled {
+		deliveries = append(deliveries, entity.NotificationDelivery{
+			UserID:  userId,
+			Channel: channel,
+			Target:  target,
+			Kind:    message.Kind,
+			Title:   message.Title,
+			Body:    message.Body,
+			Data:    message.Data,
+		})
+	}
+
+	return deliveries, nil
+}
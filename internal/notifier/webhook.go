@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stock_backend/internal/entity"
+	"time"
+)
+
+type webhookPayload struct {
+	Kind   string         `json:"kind"`
+	Title  string         `json:"title"`
+	Body   string         `json:"body"`
+	Data   map[string]any `json:"data"`
+	SentAt time.Time      `json:"sent_at"`
+}
+
+// WebhookChannel posts the message as JSON to the user's URL. When a secret is
+// configured the body is signed so receivers can verify the sender.
+type WebhookChannel struct {
+	httpClient *http.Client
+	secret     string
+}
+
+func NewWebhookChannel(httpClient *http.Client, secret string) Channel {
+	return &WebhookChannel{
+		httpClient: httpClient,
+		secret:     secret,
+	}
+}
+
+func (channel *WebhookChannel) Name() string {
+	return entity.ChannelWebhook
+}
+
+func (channel *WebhookChannel) Send(ctx context.Context, target string, message Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Kind:   message.Kind,
+		Title:  message.Title,
+		Body:   message.Body,
+		Data:   message.Data,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if channel.secret != "" {
+		mac := hmac.New(sha256.New, []byte(channel.secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := channel.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
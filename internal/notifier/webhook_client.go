@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrWebhookAddressBlocked = errors.New("webhook target resolves to an internal address")
+
+// Carrier-grade NAT space, shared inside some cloud networks
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewWebhookHTTPClient returns the client webhooks are posted with. Targets are
+// chosen by users, so it only connects to public addresses, checked on the IP
+// actually dialed so a DNS answer changed after validation is caught too, and it
+// does not follow redirects.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: blockInternalAddress,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Through a proxy the dialer would only see the address of the proxy
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func blockInternalAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublicAddress(ip) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// IsPublicAddress reports whether ip is reachable on the internet, as opposed to
+// loopback, private, link-local (such as the cloud metadata service) or unspecified
+func IsPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+
+	"github.com/lib/pq"
+)
+
+type AlertRepository interface {
+	CreateAlert(ctx context.Context, alert *entity.Alert) error
+	GetAlertsByUserID(ctx context.Context, userId string) ([]entity.Alert, error)
+	UpdateAlert(ctx context.Context, userId string, alertId string, threshold *float64, active *bool) (*entity.Alert, error)
+	DeleteAlert(ctx context.Context, userId string, alertId string) error
+	GetAlertEvents(ctx context.Context, userId string, alertId string) ([]entity.AlertEvent, error)
+	GetActiveAlerts(ctx context.Context) ([]entity.Alert, error)
+	TriggerAlert(ctx context.Context, event *entity.AlertEvent, deliveries []entity.NotificationDelivery) (bool, error)
+	RearmAlerts(ctx context.Context, alertIds []string) error
+}
+
+type AlertRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) AlertRepository {
+	return &AlertRepositoryImpl{
+		DB: db,
+	}
+}
+
+const alertColumns = "id, userid, stock, condition, threshold, active, armed, last_triggered_at, created_at, updated_at"
+
+func scanAlert(scanner interface{ Scan(dest ...any) error }) (*entity.Alert, error) {
+	var alert entity.Alert
+	var lastTriggeredAt sql.NullTime
+	err := scanner.Scan(
+		&alert.ID,
+		&alert.UserID,
+		&alert.Stock,
+		&alert.Condition,
+		&alert.Threshold,
+		&alert.Active,
+		&alert.Armed,
+		&lastTriggeredAt,
+		&alert.CreatedAt,
+		&alert.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastTriggeredAt.Valid {
+		alert.LastTriggeredAt = &lastTriggeredAt.Time
+	}
+	return &alert, nil
+}
+
+func (repository *AlertRepositoryImpl) CreateAlert(ctx context.Context, alert *entity.Alert) error {
+	ctx, span := tracing.Start(ctx, "AlertRepository.CreateAlert")
+	defer span.End()
+
+	query := `
+		INSERT INTO alerts (id, userid, stock, condition, threshold)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + alertColumns
+
+	row := repository.DB.QueryRowContext(ctx, query,
+		alert.ID,
+		alert.UserID,
+		alert.Stock,
+		alert.Condition,
+		alert.Threshold,
+	)
+
+	created, err := scanAlert(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23503" {
+				return domainerr.ErrAlertStockNotInWatchlist
+			}
+		}
+		return domainerr.ErrInternal
+	}
+
+	*alert = *created
+	return nil
+}
+
+func (repository *AlertRepositoryImpl) GetAlertsByUserID(ctx context.Context, userId string) ([]entity.Alert, error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.GetAlertsByUserID")
+	defer span.End()
+
+	query := "SELECT " + alertColumns + " FROM alerts WHERE userid = $1 ORDER BY created_at"
+	return repository.queryAlerts(ctx, query, userId)
+}
+
+func (repository *AlertRepositoryImpl) UpdateAlert(ctx context.Context, userId string, alertId string, threshold *float64, active *bool) (*entity.Alert, error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.UpdateAlert")
+	defer span.End()
+
+	// Any edit re-arms the alert so the new settings are evaluated from a clean state
+	query := `
+		UPDATE alerts
+		SET threshold = COALESCE($3, threshold),
+			active = COALESCE($4, active),
+			armed = TRUE,
+			updated_at = NOW()
+		WHERE id = $1 AND userid = $2
+		RETURNING ` + alertColumns
+
+	row := repository.DB.QueryRowContext(ctx, query, alertId, userId, threshold, active)
+	alert, err := scanAlert(row)
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrAlertNotFound
+	}
+
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return alert, nil
+}
+
+func (repository *AlertRepositoryImpl) DeleteAlert(ctx context.Context, userId string, alertId string) error {
+	ctx, span := tracing.Start(ctx, "AlertRepository.DeleteAlert")
+	defer span.End()
+
+	query := "DELETE FROM alerts WHERE id = $1 AND userid = $2"
+	res, err := repository.DB.ExecContext(ctx, query, alertId, userId)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return domainerr.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+func (repository *AlertRepositoryImpl) GetAlertEvents(ctx context.Context, userId string, alertId string) ([]entity.AlertEvent, error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.GetAlertEvents")
+	defer span.End()
+
+	var exists bool
+	err := repository.DB.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM alerts WHERE id = $1 AND userid = $2)",
+		alertId,
+		userId,
+	).Scan(&exists)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	if !exists {
+		return nil, domainerr.ErrAlertNotFound
+	}
+
+	query := `
+		SELECT id, alertid, price, change_percent, triggered_at
+		FROM alert_events
+		WHERE alertid = $1
+		ORDER BY triggered_at DESC
+	`
+	rows, err := repository.DB.QueryContext(ctx, query, alertId)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	events := []entity.AlertEvent{}
+	for rows.Next() {
+		var event entity.AlertEvent
+		if err := rows.Scan(&event.ID, &event.AlertID, &event.Price, &event.ChangePercent, &event.TriggeredAt); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return events, nil
+}
+
+func (repository *AlertRepositoryImpl) GetActiveAlerts(ctx context.Context) ([]entity.Alert, error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.GetActiveAlerts")
+	defer span.End()
+
+	query := "SELECT " + alertColumns + " FROM alerts WHERE active"
+	return repository.queryAlerts(ctx, query)
+}
+
+// TriggerAlert disarms the alert, records the event and queues the deliveries
+// of its notification in one transaction, so a disarmed alert always has its
+// notification queued. It returns false when the alert was already disarmed
+// by another evaluation.
+func (repository *AlertRepositoryImpl) TriggerAlert(ctx context.Context, event *entity.AlertEvent, deliveries []entity.NotificationDelivery) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.TriggerAlert")
+	defer span.End()
+
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logging.Error(ctx, "rollback failed", "error", err)
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE alerts SET armed = FALSE, last_triggered_at = NOW() WHERE id = $1 AND active AND armed",
+		event.AlertID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	insertQuery := `
+		INSERT INTO alert_events (alertid, price, change_percent)
+		VALUES ($1, $2, $3)
+		RETURNING id, triggered_at
+	`
+	if err := tx.QueryRowContext(ctx, insertQuery,
+		event.AlertID,
+		event.Price,
+		event.ChangePercent,
+	).Scan(&event.ID, &event.TriggeredAt); err != nil {
+		return false, err
+	}
+
+	if err := insertNotificationDeliveries(ctx, tx, deliveries); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (repository *AlertRepositoryImpl) RearmAlerts(ctx context.Context, alertIds []string) error {
+	ctx, span := tracing.Start(ctx, "AlertRepository.RearmAlerts")
+	defer span.End()
+
+	if len(alertIds) == 0 {
+		return nil
+	}
+
+	query := "UPDATE alerts SET armed = TRUE WHERE id = ANY($1) AND NOT armed"
+	_, err := repository.DB.ExecContext(ctx, query, pq.Array(alertIds))
+	return err
+}
+
+func (repository *AlertRepositoryImpl) queryAlerts(ctx context.Context, query string, args ...any) ([]entity.Alert, error) {
+	rows, err := repository.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	alerts := []entity.Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		alerts = append(alerts, *alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/tracing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache misses are loaded detached from the request that started them, since
+// other requests may be waiting on the same load
+const watchlistLoadTimeout = 2 * time.Second
+
+// storeWatchlistScript writes a loaded result only when the watchlist was not
+// changed while it was loading, so a slow read never caches data older than a write
+var storeWatchlistScript = redis.NewScript(`
+if (redis.call("GET", KEYS[2]) or "") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[2], ARGV[3])
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return 1
+`)
+
+// watchlistCacheKey is a hash holding every cached read of a user's watchlist,
+// so one DEL invalidates all of them
+func watchlistCacheKey(userId string) string {
+	return fmt.Sprintf("watchlist:%s", userId)
+}
+
+func watchlistVersionKey(userId string) string {
+	return fmt.Sprintf("watchlist:%s:version", userId)
+}
+
+// CachedWatchlistRepository is a read-through Redis cache in front of a
+// WatchlistRepository. Reads are cached per user and invalidated by every write.
+type CachedWatchlistRepository struct {
+	next  WatchlistRepository
+	cache *cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+func NewCachedWatchlistRepository(next WatchlistRepository, cache *cache.Cache, ttl time.Duration) WatchlistRepository {
+	return &CachedWatchlistRepository{
+		next:  next,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (repository *CachedWatchlistRepository) AddWatchlist(ctx context.Context, userId string, stock string) error {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.AddWatchlist")
+	defer span.End()
+
+	if err := repository.next.AddWatchlist(ctx, userId, stock); err != nil {
+		return err
+	}
+
+	repository.invalidate(ctx, userId)
+	return nil
+}
+
+func (repository *CachedWatchlistRepository) RemoveWatchlist(ctx context.Context, userId string, stock string) error {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.RemoveWatchlist")
+	defer span.End()
+
+	if err := repository.next.RemoveWatchlist(ctx, userId, stock); err != nil {
+		return err
+	}
+
+	repository.invalidate(ctx, userId)
+	return nil
+}
+
+func (repository *CachedWatchlistRepository) GetWatchlistByUserID(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.GetWatchlistByUserID")
+	defer span.End()
+
+	return readThrough(ctx, repository, userId, "stocks", func(ctx context.Context) ([]string, error) {
+		return repository.next.GetWatchlistByUserID(ctx, userId)
+	})
+}
+
+func (repository *CachedWatchlistRepository) GetWatchlistEntries(ctx context.Context, userId string, filter entity.WatchlistFilter) ([]entity.Watchlist, error) {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.GetWatchlistEntries")
+	defer span.End()
+
+	field := fmt.Sprintf("entries:%s:%s", filter.Tag, filter.Sort)
+	return readThrough(ctx, repository, userId, field, func(ctx context.Context) ([]entity.Watchlist, error) {
+		return repository.next.GetWatchlistEntries(ctx, userId, filter)
+	})
+}
+
+func (repository *CachedWatchlistRepository) UpdateWatchlistEntry(ctx context.Context, userId string, stock string, update entity.WatchlistUpdate) (*entity.Watchlist, error) {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.UpdateWatchlistEntry")
+	defer span.End()
+
+	entry, err := repository.next.UpdateWatchlistEntry(ctx, userId, stock, update)
+	if err != nil {
+		return nil, err
+	}
+
+	repository.invalidate(ctx, userId)
+	return entry, nil
+}
+
+func (repository *CachedWatchlistRepository) AddWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.AddWatchlistBatch")
+	defer span.End()
+
+	added, err := repository.next.AddWatchlistBatch(ctx, userId, stocks)
+	if err != nil {
+		return nil, err
+	}
+
+	repository.invalidate(ctx, userId)
+	return added, nil
+}
+
+func (repository *CachedWatchlistRepository) RemoveWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "CachedWatchlistRepository.RemoveWatchlistBatch")
+	defer span.End()
+
+	removed, err := repository.next.RemoveWatchlistBatch(ctx, userId, stocks)
+	if err != nil {
+		return nil, err
+	}
+
+	repository.invalidate(ctx, userId)
+	return removed, nil
+}
+
+// invalidate bumps the version so loads that started before the write do not
+// store their result, then drops the cached reads of the user. The drop is
+// retried by the cache when Redis is down.
+func (repository *CachedWatchlistRepository) invalidate(ctx context.Context, userId string) {
+	versionKey := watchlistVersionKey(userId)
+	_ = repository.cache.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, versionKey)
+			pipe.PExpire(ctx, versionKey, repository.ttl)
+			return nil
+		})
+		return err
+	})
+	repository.cache.Invalidate(ctx, watchlistCacheKey(userId))
+}
+
+// readThrough returns a cached read of a user's watchlist, loading it once for
+// all concurrent callers on a miss. A degraded cache falls back to the database.
+func readThrough[T any](ctx context.Context, repository *CachedWatchlistRepository, userId string, field string, load func(ctx context.Context) (T, error)) (T, error) {
+	key := watchlistCacheKey(userId)
+	if cached, ok := repository.cache.HGet(ctx, key, field); ok {
+		var value T
+		if err := json.Unmarshal([]byte(cached), &value); err == nil {
+			repository.cache.RecordLookup("watchlist", true)
+			return value, nil
+		}
+	}
+	repository.cache.RecordLookup("watchlist", false)
+
+	result := repository.group.DoChan(key+"|"+field, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), watchlistLoadTimeout)
+		defer cancel()
+
+		version, _ := repository.cache.Get(loadCtx, watchlistVersionKey(userId))
+
+		// The load is shared even while the cache is degraded, which keeps a
+		// Redis outage from multiplying the reads on Postgres
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err := json.Marshal(value); err == nil {
+			keys := []string{key, watchlistVersionKey(userId)}
+			_ = repository.cache.Do(loadCtx, func(ctx context.Context, client *redis.Client) error {
+				return storeWatchlistScript.Run(ctx, client, keys, version, field, data, repository.ttl.Milliseconds()).Err()
+			})
+		}
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+
+	case res := <-result:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type EmailOutboxRepository interface {
+	ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]entity.EmailOutbox, error)
+	MarkEmailSent(ctx context.Context, emailId int64) error
+	MarkEmailFailed(ctx context.Context, email entity.EmailOutbox) error
+	ReleaseEmails(ctx context.Context, emailIds []int64) error
+	GetEmails(ctx context.Context, status string, limit int) ([]entity.EmailOutbox, error)
+	RetryEmail(ctx context.Context, emailId int64) error
+}
+
+type EmailOutboxRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewEmailOutboxRepository(db *sql.DB) EmailOutboxRepository {
+	return &EmailOutboxRepositoryImpl{
+		DB: db,
+	}
+}
+
+// insertEmailOutbox queues an email inside the caller's transaction so the
+// email is only sent when the surrounding change is committed
+func insertEmailOutbox(ctx context.Context, tx *sql.Tx, email *entity.EmailOutbox) error {
+	query := `
+		INSERT INTO email_outbox (recipient, subject, text_body, html_body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, attempts, max_attempts, next_attempt_at, created_at
+	`
+	return tx.QueryRowContext(ctx, query,
+		email.Recipient,
+		email.Subject,
+		email.TextBody,
+		email.HTMLBody,
+	).Scan(&email.ID, &email.Status, &email.Attempts, &email.MaxAttempts, &email.NextAttemptAt, &email.CreatedAt)
+}
+
+func scanEmailOutbox(scanner interface{ Scan(dest ...any) error }, withBody bool) (*entity.EmailOutbox, error) {
+	var email entity.EmailOutbox
+	var sentAt sql.NullTime
+	dest := []any{
+		&email.ID,
+		&email.Recipient,
+		&email.Subject,
+		&email.Status,
+		&email.Attempts,
+		&email.MaxAttempts,
+		&email.LastError,
+		&email.NextAttemptAt,
+		&email.CreatedAt,
+		&sentAt,
+	}
+	if withBody {
+		dest = append(dest, &email.TextBody, &email.HTMLBody)
+	}
+
+	if err := scanner.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if sentAt.Valid {
+		email.SentAt = &sentAt.Time
+	}
+	return &email, nil
+}
+
+const emailOutboxColumns = "id, recipient, subject, status, attempts, max_attempts, last_error, next_attempt_at, created_at, sent_at"
+
+// ClaimDueEmails leases due emails to the caller so replicas never send the same email concurrently
+func (repository *EmailOutboxRepositoryImpl) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]entity.EmailOutbox, error) {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.ClaimDueEmails")
+	defer span.End()
+
+	query := `
+		UPDATE email_outbox
+		SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending'
+				AND next_attempt_at <= NOW()
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + emailOutboxColumns + `, text_body, html_body`
+
+	rows, err := repository.DB.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	emails := []entity.EmailOutbox{}
+	for rows.Next() {
+		email, err := scanEmailOutbox(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, *email)
+	}
+
+	return emails, rows.Err()
+}
+
+func (repository *EmailOutboxRepositoryImpl) MarkEmailSent(ctx context.Context, emailId int64) error {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.MarkEmailSent")
+	defer span.End()
+
+	query := `
+		UPDATE email_outbox
+		SET status = 'sent', attempts = attempts + 1, sent_at = NOW(), locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := repository.DB.ExecContext(ctx, query, emailId)
+	return err
+}
+
+func (repository *EmailOutboxRepositoryImpl) MarkEmailFailed(ctx context.Context, email entity.EmailOutbox) error {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.MarkEmailFailed")
+	defer span.End()
+
+	query := `
+		UPDATE email_outbox
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := repository.DB.ExecContext(ctx, query,
+		email.ID,
+		email.Status,
+		email.Attempts,
+		email.LastError,
+		email.NextAttemptAt,
+	)
+	return err
+}
+
+// ReleaseEmails drops the claim on emails that were not attempted, so they are sent without waiting for the lease to expire
+func (repository *EmailOutboxRepositoryImpl) ReleaseEmails(ctx context.Context, emailIds []int64) error {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.ReleaseEmails")
+	defer span.End()
+
+	if len(emailIds) == 0 {
+		return nil
+	}
+
+	query := "UPDATE email_outbox SET locked_until = NULL WHERE id = ANY($1) AND status = 'pending'"
+	_, err := repository.DB.ExecContext(ctx, query, pq.Array(emailIds))
+	return err
+}
+
+func (repository *EmailOutboxRepositoryImpl) GetEmails(ctx context.Context, status string, limit int) ([]entity.EmailOutbox, error) {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.GetEmails")
+	defer span.End()
+
+	query := `
+		SELECT ` + emailOutboxColumns + `
+		FROM email_outbox
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := repository.DB.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	emails := []entity.EmailOutbox{}
+	for rows.Next() {
+		email, err := scanEmailOutbox(rows, false)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		emails = append(emails, *email)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return emails, nil
+}
+
+// RetryEmail gives a failed email a fresh set of attempts
+func (repository *EmailOutboxRepositoryImpl) RetryEmail(ctx context.Context, emailId int64) error {
+	ctx, span := tracing.Start(ctx, "EmailOutboxRepository.RetryEmail")
+	defer span.End()
+
+	var status string
+	err := repository.DB.QueryRowContext(ctx, "SELECT status FROM email_outbox WHERE id = $1", emailId).Scan(&status)
+	if err == sql.ErrNoRows {
+		return domainerr.ErrEmailNotFound
+	}
+
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	query := `
+		UPDATE email_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), locked_until = NULL
+		WHERE id = $1 AND status = 'failed'
+	`
+	res, err := repository.DB.ExecContext(ctx, query, emailId)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return domainerr.ErrEmailNotRetryable
+	}
+
+	return nil
+}
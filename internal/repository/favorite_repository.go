@@ -5,35 +5,40 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/entity"
 	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
 	"time"
 
 	"github.com/lib/pq"
-	"github.com/redis/go-redis/v9"
 )
 
 type FavoriteRepository interface {
 	Create(favorite *entity.Favorite, ctx context.Context) error
-	GetFavorites(userId string, ctx context.Context) ([]string, error)
-	AddFavoriteCache(key string, favorite []string, ctx context.Context) error
+	GetFavorites(userId string, ctx context.Context) ([]entity.Underwriter, error)
+	AddFavoriteCache(key string, favorite []entity.Underwriter, ctx context.Context) error
 	RemoveFavorite(userId string, underwriterCode string, ctx context.Context) error
+	AddFavoritesBatch(userId string, underwriterCodes []string, ctx context.Context) ([]string, error)
+	RemoveFavoritesBatch(userId string, underwriterCodes []string, ctx context.Context) ([]string, error)
 }
 
 type FavoriteRepositoryImpl struct {
-	DB      *sql.DB
-	RedisDB *redis.Client
+	DB    *sql.DB
+	Cache *cache.Cache
 }
 
-func NewFavoriteRepository(db *sql.DB, redisDb *redis.Client) FavoriteRepository {
+func NewFavoriteRepository(db *sql.DB, cache *cache.Cache) FavoriteRepository {
 	return &FavoriteRepositoryImpl{
-		DB:      db,
-		RedisDB: redisDb,
+		DB:    db,
+		Cache: cache,
 	}
 }
 
 func (repository *FavoriteRepositoryImpl) Create(favorite *entity.Favorite, ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.Create")
+	defer span.End()
+
 	query := `INSERT INTO favorites (userId, underwriterId) VALUES ($1, $2)`
 
 	if _, err := repository.DB.ExecContext(ctx, query,
@@ -44,32 +49,43 @@ func (repository *FavoriteRepositoryImpl) Create(favorite *entity.Favorite, ctx
 			if pqErr.Code == "23505" {
 				return domainerr.ErrFavoritesDuplicate
 			}
+
+			if pqErr.Code == "23503" {
+				return domainerr.ErrUnderwriterNotFound
+			}
 		}
 		return err
 	}
 
 	// Invalidate the user's favorites cache so the next read gets fresh data
-	cacheKey := fmt.Sprintf("favorites:%s", favorite.UserID)
-	if err := repository.RedisDB.Del(ctx, cacheKey).Err(); err != nil {
-		log.Printf("[ERROR] error: %v", err)
-	}
+	repository.Cache.Invalidate(ctx, fmt.Sprintf("favorites:%s", favorite.UserID))
 	return nil
 }
 
-func (repository *FavoriteRepositoryImpl) GetFavorites(userId string, ctx context.Context) ([]string, error) {
+func (repository *FavoriteRepositoryImpl) GetFavorites(userId string, ctx context.Context) ([]entity.Underwriter, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.GetFavorites")
+	defer span.End()
+
 	cacheKey := fmt.Sprintf("favorites:%s", userId)
-	cachedData, err := repository.RedisDB.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var favorite []string
+	if cachedData, ok := repository.Cache.Get(ctx, cacheKey); ok {
+		var favorite []entity.Underwriter
 		if err := json.Unmarshal([]byte(cachedData), &favorite); err == nil {
+			repository.Cache.RecordLookup("favorites", true)
 			return favorite, nil
 		}
-	} else if err != redis.Nil {
-		return nil, err
 	}
-
-	// If the data is not in cache
-	rows, err := repository.DB.QueryContext(ctx, `SELECT underwriterId FROM favorites WHERE userId = $1`, userId)
+	repository.Cache.RecordLookup("favorites", false)
+
+	// If the data is not in cache, or Redis is unavailable. Favorites created before the catalog existed
+	// may have no underwriter row, so they are returned with an empty name.
+	query := `
+		SELECT f.underwriterId, COALESCE(u.name, ''), COALESCE(u.metadata, '{}')
+		FROM favorites f
+		LEFT JOIN underwriters u ON u.code = f.underwriterId
+		WHERE f.userId = $1
+		ORDER BY f.underwriterId
+	`
+	rows, err := repository.DB.QueryContext(ctx, query, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -78,18 +94,22 @@ func (repository *FavoriteRepositoryImpl) GetFavorites(userId string, ctx contex
 		_ = rows.Close()
 	}()
 
-	var favorites []entity.Favorite
+	listFavorite := []entity.Underwriter{}
 	for rows.Next() {
-		var fav entity.Favorite
-		if err := rows.Scan(&fav.UnderwriterID); err != nil {
+		var underwriter entity.Underwriter
+		var metadata []byte
+		if err := rows.Scan(&underwriter.ID, &underwriter.Name, &metadata); err != nil {
 			return nil, err
 		}
-		favorites = append(favorites, fav)
+
+		if err := json.Unmarshal(metadata, &underwriter.Metadata); err != nil {
+			return nil, err
+		}
+		listFavorite = append(listFavorite, underwriter)
 	}
 
-	listFavorite := []string{}
-	for _, fav := range favorites {
-		listFavorite = append(listFavorite, fav.UnderwriterID)
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	_ = repository.AddFavoriteCache(cacheKey, listFavorite, ctx)
@@ -97,19 +117,19 @@ func (repository *FavoriteRepositoryImpl) GetFavorites(userId string, ctx contex
 	return listFavorite, nil
 }
 
-func (repository *FavoriteRepositoryImpl) AddFavoriteCache(key string, favorites []string, ctx context.Context) error {
+func (repository *FavoriteRepositoryImpl) AddFavoriteCache(key string, favorites []entity.Underwriter, ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.AddFavoriteCache")
+	defer span.End()
+
 	if jsonData, err := json.Marshal(favorites); err == nil {
-		_ = repository.RedisDB.Set(ctx, key, jsonData, 5*time.Minute).Err()
+		repository.Cache.Set(ctx, key, jsonData, 5*time.Minute)
 	}
 	return nil
 }
 
 func (repository *FavoriteRepositoryImpl) RemoveFavorite(userId string, underwriterCode string, ctx context.Context) error {
-	// Remove the cache entry
-	cacheKey := fmt.Sprintf("favorites:%s", userId)
-	if err := repository.RedisDB.Del(ctx, cacheKey).Err(); err != nil {
-		return err
-	}
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.RemoveFavorite")
+	defer span.End()
 
 	// Delete in database
 	result, err := repository.DB.ExecContext(ctx,
@@ -131,5 +151,62 @@ func (repository *FavoriteRepositoryImpl) RemoveFavorite(userId string, underwri
 		return domainerr.ErrFavoritesNotFound
 	}
 
+	// Remove the cache entry
+	repository.Cache.Invalidate(ctx, fmt.Sprintf("favorites:%s", userId))
 	return nil
 }
+
+// AddFavoritesBatch inserts the underwriters with one statement and returns the
+// ones that were not favorited yet
+func (repository *FavoriteRepositoryImpl) AddFavoritesBatch(userId string, underwriterCodes []string, ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.AddFavoritesBatch")
+	defer span.End()
+
+	query := `
+		INSERT INTO favorites (userId, underwriterId)
+		SELECT $1, UNNEST($2::TEXT[])
+		ON CONFLICT DO NOTHING
+		RETURNING underwriterId
+	`
+	return repository.changeFavorites(userId, ctx, query, pq.Array(underwriterCodes))
+}
+
+// RemoveFavoritesBatch deletes the underwriters with one statement and returns the ones that were removed
+func (repository *FavoriteRepositoryImpl) RemoveFavoritesBatch(userId string, underwriterCodes []string, ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteRepository.RemoveFavoritesBatch")
+	defer span.End()
+
+	query := "DELETE FROM favorites WHERE userId = $1 AND underwriterId = ANY($2) RETURNING underwriterId"
+	return repository.changeFavorites(userId, ctx, query, pq.Array(underwriterCodes))
+}
+
+// changeFavorites runs a statement that returns the changed codes and invalidates the user's cache
+func (repository *FavoriteRepositoryImpl) changeFavorites(userId string, ctx context.Context, query string, codes any) ([]string, error) {
+	rows, err := repository.DB.QueryContext(ctx, query, userId, codes)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, domainerr.ErrUnderwriterNotFound
+		}
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	changed := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		changed = append(changed, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	repository.Cache.Invalidate(ctx, fmt.Sprintf("favorites:%s", userId))
+	return changed, nil
+}
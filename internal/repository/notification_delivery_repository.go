@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/tracing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type NotificationDeliveryRepository interface {
+	EnqueueDeliveries(ctx context.Context, deliveries []entity.NotificationDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error)
+	MarkDeliverySent(ctx context.Context, deliveryId int64) error
+	MarkDeliveryFailed(ctx context.Context, delivery entity.NotificationDelivery) error
+	ReleaseDeliveries(ctx context.Context, deliveryIds []int64) error
+}
+
+type NotificationDeliveryRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewNotificationDeliveryRepository(db *sql.DB) NotificationDeliveryRepository {
+	return &NotificationDeliveryRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (repository *NotificationDeliveryRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []entity.NotificationDelivery) error {
+	ctx, span := tracing.Start(ctx, "NotificationDeliveryRepository.EnqueueDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logging.Error(ctx, "rollback failed", "error", err)
+		}
+	}()
+
+	if err := insertNotificationDeliveries(ctx, tx, deliveries); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertNotificationDeliveries queues deliveries inside the caller's transaction
+// so they are only sent when the surrounding change is committed
+func insertNotificationDeliveries(ctx context.Context, tx *sql.Tx, deliveries []entity.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO notification_deliveries (userid, channel, target, kind, title, body, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	for _, delivery := range deliveries {
+		data, err := json.Marshal(delivery.Data)
+		if err != nil {
+			return err
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			delivery.UserID,
+			delivery.Channel,
+			delivery.Target,
+			delivery.Kind,
+			delivery.Title,
+			delivery.Body,
+			data,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries leases due deliveries to the caller. Leased rows are skipped by
+// other replicas until the lease expires, so a crashed worker's rows are picked up again.
+func (repository *NotificationDeliveryRepositoryImpl) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error) {
+	ctx, span := tracing.Start(ctx, "NotificationDeliveryRepository.ClaimDueDeliveries")
+	defer span.End()
+
+	query := `
+		UPDATE notification_deliveries
+		SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status = 'pending'
+				AND next_attempt_at <= NOW()
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, userid, channel, target, kind, title, body, data, status, attempts, last_error, next_attempt_at
+	`
+	rows, err := repository.DB.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deliveries := []entity.NotificationDelivery{}
+	for rows.Next() {
+		var delivery entity.NotificationDelivery
+		var data []byte
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.UserID,
+			&delivery.Channel,
+			&delivery.Target,
+			&delivery.Kind,
+			&delivery.Title,
+			&delivery.Body,
+			&data,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &delivery.Data); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (repository *NotificationDeliveryRepositoryImpl) MarkDeliverySent(ctx context.Context, deliveryId int64) error {
+	ctx, span := tracing.Start(ctx, "NotificationDeliveryRepository.MarkDeliverySent")
+	defer span.End()
+
+	query := `
+		UPDATE notification_deliveries
+		SET status = 'sent', attempts = attempts + 1, delivered_at = NOW(), locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := repository.DB.ExecContext(ctx, query, deliveryId)
+	return err
+}
+
+// MarkDeliveryFailed stores the outcome of a failed attempt. The caller decides
+// the next attempt time and whether the delivery is dead.
+func (repository *NotificationDeliveryRepositoryImpl) MarkDeliveryFailed(ctx context.Context, delivery entity.NotificationDelivery) error {
+	ctx, span := tracing.Start(ctx, "NotificationDeliveryRepository.MarkDeliveryFailed")
+	defer span.End()
+
+	query := `
+		UPDATE notification_deliveries
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := repository.DB.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+	)
+	return err
+}
+
+// ReleaseDeliveries drops the lease on deliveries that were not attempted, so they are sent without waiting for the lease to expire
+func (repository *NotificationDeliveryRepositoryImpl) ReleaseDeliveries(ctx context.Context, deliveryIds []int64) error {
+	ctx, span := tracing.Start(ctx, "NotificationDeliveryRepository.ReleaseDeliveries")
+	defer span.End()
+
+	if len(deliveryIds) == 0 {
+		return nil
+	}
+
+	query := "UPDATE notification_deliveries SET locked_until = NULL WHERE id = ANY($1) AND status = 'pending'"
+	_, err := repository.DB.ExecContext(ctx, query, pq.Array(deliveryIds))
+	return err
+}
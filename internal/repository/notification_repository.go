@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+)
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *entity.Notification) error
+	GetNotifications(ctx context.Context, userId string, unreadOnly bool) ([]entity.Notification, error)
+	SetNotificationRead(ctx context.Context, userId string, notificationId string, read bool) error
+	MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error)
+	GetPreferences(ctx context.Context, userId string) ([]entity.NotificationPreference, error)
+	UpsertPreference(ctx context.Context, preference entity.NotificationPreference) error
+	GetUserEmail(ctx context.Context, userId string) (string, error)
+}
+
+type NotificationRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &NotificationRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (repository *NotificationRepositoryImpl) CreateNotification(ctx context.Context, notification *entity.Notification) error {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.CreateNotification")
+	defer span.End()
+
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO notifications (id, userid, kind, title, body, data)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+	return repository.DB.QueryRowContext(ctx, query,
+		notification.ID,
+		notification.UserID,
+		notification.Kind,
+		notification.Title,
+		notification.Body,
+		data,
+	).Scan(&notification.CreatedAt)
+}
+
+func (repository *NotificationRepositoryImpl) GetNotifications(ctx context.Context, userId string, unreadOnly bool) ([]entity.Notification, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetNotifications")
+	defer span.End()
+
+	query := `
+		SELECT id, userid, kind, title, body, data, read_at, created_at
+		FROM notifications
+		WHERE userid = $1 AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT 100
+	`
+	rows, err := repository.DB.QueryContext(ctx, query, userId, unreadOnly)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notifications := []entity.Notification{}
+	for rows.Next() {
+		var notification entity.Notification
+		var data []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Kind,
+			&notification.Title,
+			&notification.Body,
+			&data,
+			&readAt,
+			&notification.CreatedAt,
+		); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+
+		if err := json.Unmarshal(data, &notification.Data); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+
+		if readAt.Valid {
+			notification.Read = true
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return notifications, nil
+}
+
+func (repository *NotificationRepositoryImpl) SetNotificationRead(ctx context.Context, userId string, notificationId string, read bool) error {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.SetNotificationRead")
+	defer span.End()
+
+	query := "UPDATE notifications SET read_at = NULL WHERE id = $1 AND userid = $2"
+	if read {
+		query = "UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND userid = $2"
+	}
+
+	res, err := repository.DB.ExecContext(ctx, query, notificationId, userId)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return domainerr.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (repository *NotificationRepositoryImpl) MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.MarkAllNotificationsRead")
+	defer span.End()
+
+	query := "UPDATE notifications SET read_at = NOW() WHERE userid = $1 AND read_at IS NULL"
+	res, err := repository.DB.ExecContext(ctx, query, userId)
+	if err != nil {
+		return 0, domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, domainerr.ErrInternal
+	}
+
+	return rowsAffected, nil
+}
+
+func (repository *NotificationRepositoryImpl) GetPreferences(ctx context.Context, userId string) ([]entity.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetPreferences")
+	defer span.End()
+
+	query := "SELECT userid, channel, enabled, target FROM notification_preferences WHERE userid = $1 ORDER BY channel"
+	rows, err := repository.DB.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	preferences := []entity.NotificationPreference{}
+	for rows.Next() {
+		var preference entity.NotificationPreference
+		if err := rows.Scan(&preference.UserID, &preference.Channel, &preference.Enabled, &preference.Target); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		preferences = append(preferences, preference)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return preferences, nil
+}
+
+func (repository *NotificationRepositoryImpl) UpsertPreference(ctx context.Context, preference entity.NotificationPreference) error {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.UpsertPreference")
+	defer span.End()
+
+	query := `
+		INSERT INTO notification_preferences (userid, channel, enabled, target)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (userid, channel)
+		DO UPDATE SET enabled = EXCLUDED.enabled, target = EXCLUDED.target, updated_at = NOW()
+	`
+	if _, err := repository.DB.ExecContext(ctx, query,
+		preference.UserID,
+		preference.Channel,
+		preference.Enabled,
+		preference.Target,
+	); err != nil {
+		return domainerr.ErrInternal
+	}
+
+	return nil
+}
+
+func (repository *NotificationRepositoryImpl) GetUserEmail(ctx context.Context, userId string) (string, error) {
+	ctx, span := tracing.Start(ctx, "NotificationRepository.GetUserEmail")
+	defer span.End()
+
+	var email string
+	err := repository.DB.QueryRowContext(ctx, "SELECT email FROM users WHERE id = $1", userId).Scan(&email)
+	if err == sql.ErrNoRows {
+		return "", domainerr.ErrUserNotFound
+	}
+
+	if err != nil {
+		return "", domainerr.ErrInternal
+	}
+
+	return email, nil
+}
@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+
+	"github.com/lib/pq"
+)
+
+// LedgerCheck validates the ledger of one stock after a pending change and
+// rejects the change by returning an error
+type LedgerCheck func(ledger []entity.PortfolioTransaction) error
+
+type PortfolioRepository interface {
+	CreatePortfolio(ctx context.Context, portfolio *entity.Portfolio) error
+	GetPortfolios(ctx context.Context, userId string) ([]entity.Portfolio, error)
+	GetPortfolio(ctx context.Context, userId string, portfolioId string) (*entity.Portfolio, error)
+	UpdatePortfolio(ctx context.Context, userId string, portfolioId string, name *string, costMethod *string) (*entity.Portfolio, error)
+	DeletePortfolio(ctx context.Context, userId string, portfolioId string) error
+	GetTransactions(ctx context.Context, portfolioId string, stock string) ([]entity.PortfolioTransaction, error)
+	AddTransaction(ctx context.Context, userId string, transaction *entity.PortfolioTransaction, check LedgerCheck) error
+	DeleteTransaction(ctx context.Context, userId string, portfolioId string, transactionId string, check LedgerCheck) error
+}
+
+type PortfolioRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewPortfolioRepository(db *sql.DB) PortfolioRepository {
+	return &PortfolioRepositoryImpl{
+		DB: db,
+	}
+}
+
+const portfolioColumns = "id, userid, name, cost_method, created_at, updated_at"
+
+const transactionColumns = "id, portfolioid, stock, type, quantity, price, fee, amount, ratio, note, traded_at, created_at"
+
+// Ledger entries are replayed in trade order, entries of the same time in the order they were recorded
+const ledgerOrder = " ORDER BY traded_at, created_at"
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func scanPortfolio(scanner interface{ Scan(dest ...any) error }) (*entity.Portfolio, error) {
+	var portfolio entity.Portfolio
+	err := scanner.Scan(
+		&portfolio.ID,
+		&portfolio.UserID,
+		&portfolio.Name,
+		&portfolio.CostMethod,
+		&portfolio.CreatedAt,
+		&portfolio.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+func scanTransaction(scanner interface{ Scan(dest ...any) error }) (*entity.PortfolioTransaction, error) {
+	var transaction entity.PortfolioTransaction
+	err := scanner.Scan(
+		&transaction.ID,
+		&transaction.PortfolioID,
+		&transaction.Stock,
+		&transaction.Type,
+		&transaction.Quantity,
+		&transaction.Price,
+		&transaction.Fee,
+		&transaction.Amount,
+		&transaction.Ratio,
+		&transaction.Note,
+		&transaction.TradedAt,
+		&transaction.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+func (repository *PortfolioRepositoryImpl) CreatePortfolio(ctx context.Context, portfolio *entity.Portfolio) error {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.CreatePortfolio")
+	defer span.End()
+
+	query := `
+		INSERT INTO portfolios (id, userid, name, cost_method)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + portfolioColumns
+
+	row := repository.DB.QueryRowContext(ctx, query,
+		portfolio.ID,
+		portfolio.UserID,
+		portfolio.Name,
+		portfolio.CostMethod,
+	)
+
+	created, err := scanPortfolio(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return domainerr.ErrPortfolioDuplicate
+			}
+		}
+		return domainerr.ErrInternal
+	}
+
+	*portfolio = *created
+	return nil
+}
+
+func (repository *PortfolioRepositoryImpl) GetPortfolios(ctx context.Context, userId string) ([]entity.Portfolio, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.GetPortfolios")
+	defer span.End()
+
+	query := "SELECT " + portfolioColumns + " FROM portfolios WHERE userid = $1 ORDER BY created_at"
+	rows, err := repository.DB.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	portfolios := []entity.Portfolio{}
+	for rows.Next() {
+		portfolio, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		portfolios = append(portfolios, *portfolio)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return portfolios, nil
+}
+
+func (repository *PortfolioRepositoryImpl) GetPortfolio(ctx context.Context, userId string, portfolioId string) (*entity.Portfolio, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.GetPortfolio")
+	defer span.End()
+
+	query := "SELECT " + portfolioColumns + " FROM portfolios WHERE id = $1 AND userid = $2"
+	portfolio, err := scanPortfolio(repository.DB.QueryRowContext(ctx, query, portfolioId, userId))
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrPortfolioNotFound
+	}
+
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return portfolio, nil
+}
+
+func (repository *PortfolioRepositoryImpl) UpdatePortfolio(ctx context.Context, userId string, portfolioId string, name *string, costMethod *string) (*entity.Portfolio, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.UpdatePortfolio")
+	defer span.End()
+
+	query := `
+		UPDATE portfolios
+		SET name = COALESCE($3, name),
+			cost_method = COALESCE($4, cost_method),
+			updated_at = NOW()
+		WHERE id = $1 AND userid = $2
+		RETURNING ` + portfolioColumns
+
+	row := repository.DB.QueryRowContext(ctx, query, portfolioId, userId, name, costMethod)
+	portfolio, err := scanPortfolio(row)
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrPortfolioNotFound
+	}
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return nil, domainerr.ErrPortfolioDuplicate
+			}
+		}
+		return nil, domainerr.ErrInternal
+	}
+
+	return portfolio, nil
+}
+
+func (repository *PortfolioRepositoryImpl) DeletePortfolio(ctx context.Context, userId string, portfolioId string) error {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.DeletePortfolio")
+	defer span.End()
+
+	query := "DELETE FROM portfolios WHERE id = $1 AND userid = $2"
+	res, err := repository.DB.ExecContext(ctx, query, portfolioId, userId)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return domainerr.ErrPortfolioNotFound
+	}
+
+	return nil
+}
+
+// GetTransactions returns the ledger of a portfolio in trade order, limited to one stock when stock is set
+func (repository *PortfolioRepositoryImpl) GetTransactions(ctx context.Context, portfolioId string, stock string) ([]entity.PortfolioTransaction, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.GetTransactions")
+	defer span.End()
+
+	query := "SELECT " + transactionColumns + " FROM portfolio_transactions WHERE portfolioid = $1 AND ($2 = '' OR stock = $2)" + ledgerOrder
+	return queryTransactions(ctx, repository.DB, query, portfolioId, stock)
+}
+
+// AddTransaction records a transaction after check accepts the resulting ledger
+// of its stock. The portfolio row is locked so concurrent changes to the same
+// ledger are checked one after another.
+func (repository *PortfolioRepositoryImpl) AddTransaction(ctx context.Context, userId string, transaction *entity.PortfolioTransaction, check LedgerCheck) error {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.AddTransaction")
+	defer span.End()
+
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logging.Error(ctx, "rollback failed", "error", err)
+		}
+	}()
+
+	ledger, err := lockLedger(ctx, tx, userId, transaction.PortfolioID.String(), transaction.Stock)
+	if err != nil {
+		return err
+	}
+
+	if err := check(append(ledger, *transaction)); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO portfolio_transactions (id, portfolioid, stock, type, quantity, price, fee, amount, ratio, note, traded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + transactionColumns
+
+	row := tx.QueryRowContext(ctx, query,
+		transaction.ID,
+		transaction.PortfolioID,
+		transaction.Stock,
+		transaction.Type,
+		transaction.Quantity,
+		transaction.Price,
+		transaction.Fee,
+		transaction.Amount,
+		transaction.Ratio,
+		transaction.Note,
+		transaction.TradedAt,
+	)
+
+	created, err := scanTransaction(row)
+	if err != nil {
+		logging.Error(ctx, "insert portfolio transaction failed", "error", err)
+		return domainerr.ErrInternal
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domainerr.ErrInternal
+	}
+
+	*transaction = *created
+	return nil
+}
+
+// DeleteTransaction removes a transaction after check accepts the ledger of its stock without it
+func (repository *PortfolioRepositoryImpl) DeleteTransaction(ctx context.Context, userId string, portfolioId string, transactionId string, check LedgerCheck) error {
+	ctx, span := tracing.Start(ctx, "PortfolioRepository.DeleteTransaction")
+	defer span.End()
+
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logging.Error(ctx, "rollback failed", "error", err)
+		}
+	}()
+
+	var stock string
+	err = tx.QueryRowContext(ctx,
+		"SELECT stock FROM portfolio_transactions WHERE id = $1 AND portfolioid = $2",
+		transactionId,
+		portfolioId,
+	).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return domainerr.ErrPortfolioTransactionNotFound
+	}
+
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	ledger, err := lockLedger(ctx, tx, userId, portfolioId, stock)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]entity.PortfolioTransaction, 0, len(ledger))
+	for _, transaction := range ledger {
+		if transaction.ID.String() != transactionId {
+			remaining = append(remaining, transaction)
+		}
+	}
+
+	if err := check(remaining); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM portfolio_transactions WHERE id = $1", transactionId); err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domainerr.ErrInternal
+	}
+
+	return nil
+}
+
+// lockLedger locks a portfolio owned by the user and returns the ledger of one of its stocks
+func lockLedger(ctx context.Context, tx *sql.Tx, userId string, portfolioId string, stock string) ([]entity.PortfolioTransaction, error) {
+	var id string
+	err := tx.QueryRowContext(ctx,
+		"SELECT id FROM portfolios WHERE id = $1 AND userid = $2 FOR UPDATE",
+		portfolioId,
+		userId,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrPortfolioNotFound
+	}
+
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	query := "SELECT " + transactionColumns + " FROM portfolio_transactions WHERE portfolioid = $1 AND stock = $2" + ledgerOrder
+	return queryTransactions(ctx, tx, query, portfolioId, stock)
+}
+
+func queryTransactions(ctx context.Context, db queryer, query string, args ...any) ([]entity.PortfolioTransaction, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	transactions := []entity.PortfolioTransaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return transactions, nil
+}
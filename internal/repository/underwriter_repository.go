@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type UnderwriterRepository interface {
+	GetUnderwriters(ctx context.Context, search string) ([]entity.Underwriter, error)
+	Exists(ctx context.Context, code string) (bool, error)
+	UpsertUnderwriters(ctx context.Context, underwriters []entity.Underwriter) error
+}
+
+type UnderwriterRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewUnderwriterRepository(db *sql.DB) UnderwriterRepository {
+	return &UnderwriterRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetUnderwriters returns the catalog, optionally filtered by a code or name fragment
+func (repository *UnderwriterRepositoryImpl) GetUnderwriters(ctx context.Context, search string) ([]entity.Underwriter, error) {
+	ctx, span := tracing.Start(ctx, "UnderwriterRepository.GetUnderwriters")
+	defer span.End()
+
+	query := `
+		SELECT code, name, metadata
+		FROM underwriters
+		WHERE $1 = '' OR code ILIKE $1 || '%' ESCAPE '\' OR name ILIKE '%' || $1 || '%' ESCAPE '\'
+		ORDER BY code
+	`
+	// The search is matched literally, % and _ are not wildcards
+	rows, err := repository.DB.QueryContext(ctx, query, likeEscaper.Replace(search))
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	underwriters := []entity.Underwriter{}
+	for rows.Next() {
+		var underwriter entity.Underwriter
+		var metadata []byte
+		if err := rows.Scan(&underwriter.ID, &underwriter.Name, &metadata); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+
+		if err := json.Unmarshal(metadata, &underwriter.Metadata); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		underwriters = append(underwriters, underwriter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return underwriters, nil
+}
+
+func (repository *UnderwriterRepositoryImpl) Exists(ctx context.Context, code string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UnderwriterRepository.Exists")
+	defer span.End()
+
+	var exists bool
+	err := repository.DB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM underwriters WHERE code = $1)", code).Scan(&exists)
+	if err != nil {
+		return false, domainerr.ErrInternal
+	}
+
+	return exists, nil
+}
+
+// UpsertUnderwriters inserts new underwriters and refreshes the name and metadata of known ones
+func (repository *UnderwriterRepositoryImpl) UpsertUnderwriters(ctx context.Context, underwriters []entity.Underwriter) error {
+	ctx, span := tracing.Start(ctx, "UnderwriterRepository.UpsertUnderwriters")
+	defer span.End()
+
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logging.Error(ctx, "rollback failed", "error", err)
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO underwriters (code, name, metadata)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (code)
+		DO UPDATE SET name = EXCLUDED.name, metadata = EXCLUDED.metadata, updated_at = NOW()
+	`)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	for _, underwriter := range underwriters {
+		metadata := underwriter.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+
+		if _, err := stmt.ExecContext(ctx, underwriter.ID, underwriter.Name, data); err != nil {
+			logging.Error(ctx, "upsert underwriter failed", "underwriter_id", underwriter.ID, "error", err)
+			return domainerr.ErrInternal
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domainerr.ErrInternal
+	}
+
+	return nil
+}
@@ -4,17 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"stock_backend/internal/cache"
 	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
 	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
 
 	"github.com/lib/pq"
-	"github.com/redis/go-redis/v9"
 )
 
 type UserRepository interface {
 	GetUser(email string, ctx context.Context) (*entity.User, error)
-	Create(user entity.User, ctx context.Context) (*entity.User, error)
+	Create(user entity.User, verificationEmail *entity.EmailOutbox, ctx context.Context) (*entity.User, error)
 	VerifyUser(userId string, ctx context.Context) error
 	Logout(userId string, ctx context.Context) error
 	DeleteUser(userId string, ctx context.Context) error
@@ -22,18 +23,21 @@ type UserRepository interface {
 }
 
 type UserRepositoryImpl struct {
-	DB      *sql.DB
-	RedisDB *redis.Client
+	DB    *sql.DB
+	Cache *cache.Cache
 }
 
-func NewUserRepository(db *sql.DB, redis_db *redis.Client) UserRepository {
+func NewUserRepository(db *sql.DB, cache *cache.Cache) UserRepository {
 	return &UserRepositoryImpl{
-		DB:      db,
-		RedisDB: redis_db,
+		DB:    db,
+		Cache: cache,
 	}
 }
 
 func (repository *UserRepositoryImpl) GetUser(email string, ctx context.Context) (*entity.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUser")
+	defer span.End()
+
 	query := "SELECT id, username, email, password, r.rolename, verified FROM users u JOIN roles r ON u.roleid = r.roleid WHERE email = $1"
 	row := repository.DB.QueryRowContext(ctx, query, email)
 
@@ -50,7 +54,11 @@ func (repository *UserRepositoryImpl) GetUser(email string, ctx context.Context)
 	return &user, nil
 }
 
-func (repository *UserRepositoryImpl) Create(user entity.User, ctx context.Context) (*entity.User, error) {
+// Create inserts the user and queues the verification email in the same transaction
+func (repository *UserRepositoryImpl) Create(user entity.User, verificationEmail *entity.EmailOutbox, ctx context.Context) (*entity.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.Create")
+	defer span.End()
+
 	tx, err := repository.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -58,14 +66,14 @@ func (repository *UserRepositoryImpl) Create(user entity.User, ctx context.Conte
 
 	defer func() {
 		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
-			log.Printf("[ERROR] error rollback: %v", err)
+			logging.Error(ctx, "rollback failed", "error", err)
 		}
 	}()
 
 	insertQuery := `
-		INSERT INTO users (id, username, email, password)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, username, email, verified;
+		INSERT INTO users (id, username, email, password, locale)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, username, email, verified, locale;
 	`
 	var createdUser entity.User
 	err = tx.QueryRowContext(
@@ -75,7 +83,8 @@ func (repository *UserRepositoryImpl) Create(user entity.User, ctx context.Conte
 		user.Username,
 		user.Email,
 		user.Password,
-	).Scan(&createdUser.ID, &createdUser.Username, &createdUser.Email, &createdUser.Verified)
+		user.Locale,
+	).Scan(&createdUser.ID, &createdUser.Username, &createdUser.Email, &createdUser.Verified, &createdUser.Locale)
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -86,10 +95,19 @@ func (repository *UserRepositoryImpl) Create(user entity.User, ctx context.Conte
 		return nil, domainerr.ErrInternal
 	}
 
+	if verificationEmail != nil {
+		if err := insertEmailOutbox(ctx, tx, verificationEmail); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+	}
+
 	return &createdUser, tx.Commit()
 }
 
 func (repository *UserRepositoryImpl) VerifyUser(userId string, ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.VerifyUser")
+	defer span.End()
+
 	query := "UPDATE users SET verified = TRUE WHERE id = $1 AND verified = FALSE"
 	res, err := repository.DB.ExecContext(ctx, query, userId)
 	if err != nil {
@@ -108,20 +126,25 @@ func (repository *UserRepositoryImpl) VerifyUser(userId string, ctx context.Cont
 }
 
 func (repository *UserRepositoryImpl) Logout(userId string, ctx context.Context) error {
-	// Remove user favorites from Redis cache
-	if repository.RedisDB != nil {
-		if err := repository.RedisDB.Del(
-			ctx,
+	ctx, span := tracing.Start(ctx, "UserRepository.Logout")
+	defer span.End()
+
+	// Remove user favorites and watchlist from Redis cache. Keys that cannot be
+	// removed while Redis is down are retried by the cache, so logout never fails on them.
+	if repository.Cache != nil {
+		repository.Cache.Invalidate(ctx,
 			fmt.Sprintf("favorites:%s", userId),
-		).Err(); err != nil {
-			return domainerr.ErrInternal
-		}
+			watchlistCacheKey(userId),
+		)
 	}
 
 	return nil
 }
 
 func (repository *UserRepositoryImpl) DeleteUser(userId string, ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.DeleteUser")
+	defer span.End()
+
 	query := "DELETE FROM users WHERE id = $1"
 	res, err := repository.DB.ExecContext(ctx, query, userId)
 	if err != nil {
@@ -137,14 +160,21 @@ func (repository *UserRepositoryImpl) DeleteUser(userId string, ctx context.Cont
 		return domainerr.ErrUserNotFound
 	}
 
-	// Remove user favorites from Redis cache
-	if repository.RedisDB != nil {
-		_ = repository.RedisDB.Del(ctx, fmt.Sprintf("favorites:%s", userId)).Err()
+	// Remove user favorites and watchlist from Redis cache
+	if repository.Cache != nil {
+		repository.Cache.Invalidate(ctx,
+			fmt.Sprintf("favorites:%s", userId),
+			watchlistCacheKey(userId),
+			watchlistVersionKey(userId),
+		)
 	}
 	return nil
 }
 
 func (repository *UserRepositoryImpl) GetUserByID(userId string, ctx context.Context) (*entity.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUserByID")
+	defer span.End()
+
 	query := "SELECT id, username, email FROM users WHERE id = $1"
 	row := repository.DB.QueryRowContext(ctx, query, userId)
 
@@ -3,15 +3,22 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"stock_backend/internal/entity"
 	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
 
 	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 )
 
 type WatchlistRepository interface {
 	AddWatchlist(ctx context.Context, userId string, stock string) error
 	RemoveWatchlist(ctx context.Context, userId string, stock string) error
 	GetWatchlistByUserID(ctx context.Context, userId string) ([]string, error)
+	GetWatchlistEntries(ctx context.Context, userId string, filter entity.WatchlistFilter) ([]entity.Watchlist, error)
+	UpdateWatchlistEntry(ctx context.Context, userId string, stock string, update entity.WatchlistUpdate) (*entity.Watchlist, error)
+	AddWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error)
+	RemoveWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error)
 }
 
 type WatchlistRepositoryImpl struct {
@@ -25,6 +32,9 @@ func NewWatchlistRepository(db *sql.DB) WatchlistRepository {
 }
 
 func (repository *WatchlistRepositoryImpl) AddWatchlist(ctx context.Context, userId string, stock string) error {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.AddWatchlist")
+	defer span.End()
+
 	query := "INSERT INTO watchlist (userid, stock) VALUES ($1, $2)"
 	_, err := repository.DB.ExecContext(ctx, query, userId, stock)
 
@@ -41,6 +51,9 @@ func (repository *WatchlistRepositoryImpl) AddWatchlist(ctx context.Context, use
 }
 
 func (repository *WatchlistRepositoryImpl) RemoveWatchlist(ctx context.Context, userId string, stock string) error {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.RemoveWatchlist")
+	defer span.End()
+
 	query := "DELETE FROM watchlist WHERE userid = $1 AND stock = $2"
 	res, err := repository.DB.ExecContext(ctx, query, userId, stock)
 	if err != nil {
@@ -60,6 +73,9 @@ func (repository *WatchlistRepositoryImpl) RemoveWatchlist(ctx context.Context,
 }
 
 func (repository *WatchlistRepositoryImpl) GetWatchlistByUserID(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.GetWatchlistByUserID")
+	defer span.End()
+
 	query := "SELECT stock FROM watchlist WHERE userid = $1"
 	rows, err := repository.DB.QueryContext(ctx, query, userId)
 
@@ -86,3 +102,164 @@ func (repository *WatchlistRepositoryImpl) GetWatchlistByUserID(ctx context.Cont
 
 	return watchlist, nil
 }
+
+const watchlistColumns = "userid, stock, added_at, note, target_buy, target_sell, tags"
+
+// ORDER BY clauses for the supported sort keys
+var watchlistOrderBy = map[string]string{
+	"":                              "added_at DESC, stock",
+	entity.WatchlistSortAddedAt:     "added_at, stock",
+	entity.WatchlistSortAddedAtDesc: "added_at DESC, stock",
+	entity.WatchlistSortStock:       "stock",
+	entity.WatchlistSortStockDesc:   "stock DESC",
+}
+
+func scanWatchlist(scanner interface{ Scan(dest ...any) error }) (*entity.Watchlist, error) {
+	var watchlist entity.Watchlist
+	var targetBuy, targetSell decimal.NullDecimal
+	err := scanner.Scan(
+		&watchlist.UserId,
+		&watchlist.Stock,
+		&watchlist.AddedAt,
+		&watchlist.Note,
+		&targetBuy,
+		&targetSell,
+		pq.Array(&watchlist.Tags),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetBuy.Valid {
+		watchlist.TargetBuy = &targetBuy.Decimal
+	}
+
+	if targetSell.Valid {
+		watchlist.TargetSell = &targetSell.Decimal
+	}
+	return &watchlist, nil
+}
+
+func (repository *WatchlistRepositoryImpl) GetWatchlistEntries(ctx context.Context, userId string, filter entity.WatchlistFilter) ([]entity.Watchlist, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.GetWatchlistEntries")
+	defer span.End()
+
+	orderBy, ok := watchlistOrderBy[filter.Sort]
+	if !ok {
+		return nil, domainerr.ErrWatchlistSortInvalid
+	}
+
+	query := `
+		SELECT ` + watchlistColumns + `
+		FROM watchlist
+		WHERE userid = $1 AND ($2 = '' OR $2 = ANY(tags))
+		ORDER BY ` + orderBy
+
+	rows, err := repository.DB.QueryContext(ctx, query, userId, filter.Tag)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	entries := []entity.Watchlist{}
+	for rows.Next() {
+		entry, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		entries = append(entries, *entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return entries, nil
+}
+
+func (repository *WatchlistRepositoryImpl) UpdateWatchlistEntry(ctx context.Context, userId string, stock string, update entity.WatchlistUpdate) (*entity.Watchlist, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.UpdateWatchlistEntry")
+	defer span.End()
+
+	var tags any
+	if update.Tags != nil {
+		tags = pq.Array(update.Tags)
+	}
+
+	query := `
+		UPDATE watchlist
+		SET note = COALESCE($3, note),
+			target_buy = CASE WHEN $4::NUMERIC IS NULL THEN target_buy ELSE NULLIF($4::NUMERIC, 0) END,
+			target_sell = CASE WHEN $5::NUMERIC IS NULL THEN target_sell ELSE NULLIF($5::NUMERIC, 0) END,
+			tags = COALESCE($6::TEXT[], tags)
+		WHERE userid = $1 AND stock = $2
+		RETURNING ` + watchlistColumns
+
+	row := repository.DB.QueryRowContext(ctx, query, userId, stock, update.Note, update.TargetBuy, update.TargetSell, tags)
+	entry, err := scanWatchlist(row)
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrWatchlistNotFound
+	}
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "watchlist_target_range" {
+			return nil, domainerr.ErrWatchlistTargetInvalid
+		}
+		return nil, domainerr.ErrInternal
+	}
+
+	return entry, nil
+}
+
+// AddWatchlistBatch inserts the stocks with one statement and returns the ones
+// that were not in the watchlist yet
+func (repository *WatchlistRepositoryImpl) AddWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.AddWatchlistBatch")
+	defer span.End()
+
+	query := `
+		INSERT INTO watchlist (userid, stock)
+		SELECT $1, UNNEST($2::TEXT[])
+		ON CONFLICT DO NOTHING
+		RETURNING stock
+	`
+	return repository.queryStocks(ctx, query, userId, pq.Array(stocks))
+}
+
+// RemoveWatchlistBatch deletes the stocks with one statement and returns the ones that were removed
+func (repository *WatchlistRepositoryImpl) RemoveWatchlistBatch(ctx context.Context, userId string, stocks []string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistRepository.RemoveWatchlistBatch")
+	defer span.End()
+
+	query := "DELETE FROM watchlist WHERE userid = $1 AND stock = ANY($2) RETURNING stock"
+	return repository.queryStocks(ctx, query, userId, pq.Array(stocks))
+}
+
+func (repository *WatchlistRepositoryImpl) queryStocks(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := repository.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	stocks := []string{}
+	for rows.Next() {
+		var stock string
+		if err := rows.Scan(&stock); err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		stocks = append(stocks, stock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return stocks, nil
+}
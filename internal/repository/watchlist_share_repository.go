@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/tracing"
+)
+
+type WatchlistShareRepository interface {
+	CreateShare(ctx context.Context, share *entity.WatchlistShare) error
+	GetShares(ctx context.Context, userId string) ([]entity.WatchlistShare, error)
+	RotateShare(ctx context.Context, userId string, shareId string, slug string) (*entity.WatchlistShare, error)
+	RevokeShare(ctx context.Context, userId string, shareId string) error
+	ViewShare(ctx context.Context, slug string) (*entity.WatchlistShare, error)
+}
+
+type WatchlistShareRepositoryImpl struct {
+	DB *sql.DB
+}
+
+func NewWatchlistShareRepository(db *sql.DB) WatchlistShareRepository {
+	return &WatchlistShareRepositoryImpl{
+		DB: db,
+	}
+}
+
+const watchlistShareColumns = "id, userid, slug, title, tag, expires_at, revoked_at, view_count, last_viewed_at, created_at, updated_at"
+
+// A share link is live until it is revoked or expires
+const liveShareCondition = "revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())"
+
+func scanWatchlistShare(scanner interface{ Scan(dest ...any) error }) (*entity.WatchlistShare, error) {
+	var share entity.WatchlistShare
+	var expiresAt, revokedAt, lastViewedAt sql.NullTime
+	err := scanner.Scan(
+		&share.ID,
+		&share.UserID,
+		&share.Slug,
+		&share.Title,
+		&share.Tag,
+		&expiresAt,
+		&revokedAt,
+		&share.ViewCount,
+		&lastViewedAt,
+		&share.CreatedAt,
+		&share.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		share.ExpiresAt = &expiresAt.Time
+	}
+
+	if revokedAt.Valid {
+		share.RevokedAt = &revokedAt.Time
+	}
+
+	if lastViewedAt.Valid {
+		share.LastViewedAt = &lastViewedAt.Time
+	}
+	return &share, nil
+}
+
+func (repository *WatchlistShareRepositoryImpl) CreateShare(ctx context.Context, share *entity.WatchlistShare) error {
+	ctx, span := tracing.Start(ctx, "WatchlistShareRepository.CreateShare")
+	defer span.End()
+
+	query := `
+		INSERT INTO watchlist_shares (id, userid, slug, title, tag, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + watchlistShareColumns
+
+	row := repository.DB.QueryRowContext(ctx, query,
+		share.ID,
+		share.UserID,
+		share.Slug,
+		share.Title,
+		share.Tag,
+		share.ExpiresAt,
+	)
+
+	created, err := scanWatchlistShare(row)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	*share = *created
+	return nil
+}
+
+func (repository *WatchlistShareRepositoryImpl) GetShares(ctx context.Context, userId string) ([]entity.WatchlistShare, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareRepository.GetShares")
+	defer span.End()
+
+	query := "SELECT " + watchlistShareColumns + " FROM watchlist_shares WHERE userid = $1 ORDER BY created_at"
+	rows, err := repository.DB.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	shares := []entity.WatchlistShare{}
+	for rows.Next() {
+		share, err := scanWatchlistShare(rows)
+		if err != nil {
+			return nil, domainerr.ErrInternal
+		}
+		shares = append(shares, *share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return shares, nil
+}
+
+// RotateShare replaces the slug of a live share so the old link stops working
+func (repository *WatchlistShareRepositoryImpl) RotateShare(ctx context.Context, userId string, shareId string, slug string) (*entity.WatchlistShare, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareRepository.RotateShare")
+	defer span.End()
+
+	query := `
+		UPDATE watchlist_shares
+		SET slug = $3, updated_at = NOW()
+		WHERE id = $1 AND userid = $2 AND ` + liveShareCondition + `
+		RETURNING ` + watchlistShareColumns
+
+	share, err := scanWatchlistShare(repository.DB.QueryRowContext(ctx, query, shareId, userId, slug))
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrWatchlistShareNotFound
+	}
+
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return share, nil
+}
+
+// RevokeShare disables a share link. The row is kept so its view count stays visible to the owner.
+func (repository *WatchlistShareRepositoryImpl) RevokeShare(ctx context.Context, userId string, shareId string) error {
+	ctx, span := tracing.Start(ctx, "WatchlistShareRepository.RevokeShare")
+	defer span.End()
+
+	query := `
+		UPDATE watchlist_shares
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND userid = $2 AND revoked_at IS NULL
+	`
+	res, err := repository.DB.ExecContext(ctx, query, shareId, userId)
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domainerr.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return domainerr.ErrWatchlistShareNotFound
+	}
+
+	return nil
+}
+
+// ViewShare counts a view of a live share and returns it
+func (repository *WatchlistShareRepositoryImpl) ViewShare(ctx context.Context, slug string) (*entity.WatchlistShare, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareRepository.ViewShare")
+	defer span.End()
+
+	query := `
+		UPDATE watchlist_shares
+		SET view_count = view_count + 1, last_viewed_at = NOW()
+		WHERE slug = $1 AND ` + liveShareCondition + `
+		RETURNING ` + watchlistShareColumns
+
+	share, err := scanWatchlistShare(repository.DB.QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, domainerr.ErrWatchlistShareNotFound
+	}
+
+	if err != nil {
+		return nil, domainerr.ErrInternal
+	}
+
+	return share, nil
+}
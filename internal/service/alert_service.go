@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+type AlertService interface {
+	CreateAlert(ctx context.Context, userId string, request request.CreateAlertRequest) (*response.CreateAlertResponse, error)
+	GetAlerts(ctx context.Context, userId string) (*response.GetAlertsResponse, error)
+	UpdateAlert(ctx context.Context, userId string, alertId string, request request.UpdateAlertRequest) (*response.UpdateAlertResponse, error)
+	DeleteAlert(ctx context.Context, userId string, alertId string) (*response.DeleteAlertResponse, error)
+	GetAlertEvents(ctx context.Context, userId string, alertId string) (*response.GetAlertEventsResponse, error)
+}
+
+type AlertServiceImpl struct {
+	Repository repository.AlertRepository
+}
+
+func NewAlertService(repository repository.AlertRepository) AlertService {
+	return &AlertServiceImpl{
+		Repository: repository,
+	}
+}
+
+func (service *AlertServiceImpl) CreateAlert(ctx context.Context, userId string, request request.CreateAlertRequest) (*response.CreateAlertResponse, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.CreateAlert")
+	defer span.End()
+
+	symbol, err := helper.NormalizeSymbol(request.Stock)
+	if err != nil {
+		return nil, err
+	}
+
+	alert := &entity.Alert{
+		ID:        uuid.New(),
+		UserID:    userId,
+		Stock:     symbol.String(),
+		Condition: request.Condition,
+		Threshold: request.Threshold,
+	}
+
+	if err := service.Repository.CreateAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	response := &response.CreateAlertResponse{
+		Message: "Alert created successfully",
+		Data:    *alert,
+	}
+	return response, nil
+}
+
+func (service *AlertServiceImpl) GetAlerts(ctx context.Context, userId string) (*response.GetAlertsResponse, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.GetAlerts")
+	defer span.End()
+
+	alerts, err := service.Repository.GetAlertsByUserID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetAlertsResponse{
+		Message: "Alerts retrieved successfully",
+		Data:    alerts,
+	}
+	return response, nil
+}
+
+func (service *AlertServiceImpl) UpdateAlert(ctx context.Context, userId string, alertId string, request request.UpdateAlertRequest) (*response.UpdateAlertResponse, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.UpdateAlert")
+	defer span.End()
+
+	alert, err := service.Repository.UpdateAlert(ctx, userId, alertId, request.Threshold, request.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.UpdateAlertResponse{
+		Message: "Alert updated successfully",
+		Data:    *alert,
+	}
+	return response, nil
+}
+
+func (service *AlertServiceImpl) DeleteAlert(ctx context.Context, userId string, alertId string) (*response.DeleteAlertResponse, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.DeleteAlert")
+	defer span.End()
+
+	if err := service.Repository.DeleteAlert(ctx, userId, alertId); err != nil {
+		return nil, err
+	}
+
+	response := &response.DeleteAlertResponse{
+		Message: "Alert deleted successfully",
+	}
+	return response, nil
+}
+
+func (service *AlertServiceImpl) GetAlertEvents(ctx context.Context, userId string, alertId string) (*response.GetAlertEventsResponse, error) {
+	ctx, span := tracing.Start(ctx, "AlertService.GetAlertEvents")
+	defer span.End()
+
+	events, err := service.Repository.GetAlertEvents(ctx, userId, alertId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetAlertEventsResponse{
+		Message: "Alert events retrieved successfully",
+		Data:    events,
+	}
+	return response, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/response"
+	"strings"
+)
+
+// Largest file accepted by the import endpoints
+const MaxImportRows = 500
+
+// newBulkRows normalizes the codes of an import file or batch request and fails
+// the rows that normalize rejects or that repeat an earlier row
+func newBulkRows(codes []string, normalize func(code string) (string, error)) ([]response.ImportRowResult, error) {
+	if len(codes) == 0 {
+		return nil, domainerr.ErrImportEmpty
+	}
+
+	if len(codes) > MaxImportRows {
+		return nil, domainerr.ErrImportTooLarge
+	}
+
+	rows := make([]response.ImportRowResult, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for i, raw := range codes {
+		code, err := normalize(raw)
+		if err != nil {
+			code = strings.ToUpper(strings.TrimSpace(raw))
+		}
+		rows[i] = response.ImportRowResult{Row: i + 1, Code: code}
+
+		switch {
+		case err != nil:
+			rows[i].Status = response.ImportRowFailed
+			rows[i].Error = err.Error()
+		case seen[code]:
+			rows[i].Status = response.ImportRowFailed
+			rows[i].Error = domainerr.ErrImportDuplicateRow.Error()
+		}
+		seen[code] = true
+	}
+
+	return rows, nil
+}
+
+// normalizeStock returns the EXCHANGE:CODE form of a stock symbol
+func normalizeStock(raw string) (string, error) {
+	symbol, err := helper.NormalizeSymbol(raw)
+	if err != nil {
+		return "", err
+	}
+	return symbol.String(), nil
+}
+
+// normalizeUnderwriter returns the uppercase form of a two-letter underwriter code
+func normalizeUnderwriter(raw string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if !underwriterCodePattern.MatchString(code) {
+		return "", domainerr.ErrImportInvalidCode
+	}
+	return code, nil
+}
+
+// pendingBulkCodes returns the codes of rows that have not failed yet
+func pendingBulkCodes(rows []response.ImportRowResult) []string {
+	codes := []string{}
+	for _, row := range rows {
+		if row.Status == "" {
+			codes = append(codes, row.Code)
+		}
+	}
+	return codes
+}
+
+// failBulkRows fails every pending row that has an error message for its code
+func failBulkRows(rows []response.ImportRowResult, messages map[string]string) {
+	for i := range rows {
+		if message, ok := messages[rows[i].Code]; ok && rows[i].Status == "" {
+			rows[i].Status = response.ImportRowFailed
+			rows[i].Error = message
+		}
+	}
+}
+
+// finishImport marks the inserted rows as imported and the remaining ones as
+// failed with alreadyExists, which is what a conflicting insert means
+func finishImport(rows []response.ImportRowResult, inserted []string, alreadyExists error) *response.ImportResponse {
+	insertedSet := make(map[string]bool, len(inserted))
+	for _, code := range inserted {
+		insertedSet[code] = true
+	}
+
+	result := &response.ImportResponse{Rows: rows}
+	for i := range rows {
+		if rows[i].Status == "" {
+			if insertedSet[rows[i].Code] {
+				rows[i].Status = response.ImportRowImported
+			} else {
+				rows[i].Status = response.ImportRowFailed
+				rows[i].Error = alreadyExists.Error()
+			}
+		}
+
+		if rows[i].Status == response.ImportRowImported {
+			result.Imported++
+		} else {
+			result.Failed++
+		}
+	}
+
+	result.Message = fmt.Sprintf("Imported %d of %d rows", result.Imported, len(rows))
+	return result
+}
+
+// finishBatch marks the changed rows with status and the remaining ones as failed
+// with unchanged, then reports the rows as batch items
+func finishBatch(rows []response.ImportRowResult, changed []string, status string, unchanged error) *response.BatchResponse {
+	changedSet := make(map[string]bool, len(changed))
+	for _, code := range changed {
+		changedSet[strings.TrimSpace(code)] = true
+	}
+
+	result := &response.BatchResponse{Items: make([]response.BatchItemResult, 0, len(rows))}
+	for _, row := range rows {
+		item := response.BatchItemResult{Code: row.Code, Status: row.Status, Error: row.Error}
+		if item.Status == "" {
+			if changedSet[row.Code] {
+				item.Status = status
+			} else {
+				item.Status = response.BatchItemFailed
+				item.Error = unchanged.Error()
+			}
+		}
+
+		if item.Status == status {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	result.Message = fmt.Sprintf("%d of %d items succeeded", result.Succeeded, len(rows))
+	return result
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+)
+
+type EmailOutboxService interface {
+	GetEmails(ctx context.Context, status string) (*response.GetEmailsResponse, error)
+	RetryEmail(ctx context.Context, emailId int64) (*response.RetryEmailResponse, error)
+}
+
+type EmailOutboxServiceImpl struct {
+	Repository repository.EmailOutboxRepository
+}
+
+func NewEmailOutboxService(repository repository.EmailOutboxRepository) EmailOutboxService {
+	return &EmailOutboxServiceImpl{
+		Repository: repository,
+	}
+}
+
+func (service *EmailOutboxServiceImpl) GetEmails(ctx context.Context, status string) (*response.GetEmailsResponse, error) {
+	ctx, span := tracing.Start(ctx, "EmailOutboxService.GetEmails")
+	defer span.End()
+
+	switch status {
+	case "", entity.EmailPending, entity.EmailSent, entity.EmailFailed:
+	default:
+		return nil, domainerr.ErrEmailStatusInvalid
+	}
+
+	emails, err := service.Repository.GetEmails(ctx, status, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetEmailsResponse{
+		Message: "Emails retrieved successfully",
+		Data:    emails,
+	}
+	return response, nil
+}
+
+func (service *EmailOutboxServiceImpl) RetryEmail(ctx context.Context, emailId int64) (*response.RetryEmailResponse, error) {
+	ctx, span := tracing.Start(ctx, "EmailOutboxService.RetryEmail")
+	defer span.End()
+
+	if err := service.Repository.RetryEmail(ctx, emailId); err != nil {
+		return nil, err
+	}
+
+	response := &response.RetryEmailResponse{
+		Message: fmt.Sprintf("Email %d queued for retry", emailId),
+	}
+	return response, nil
+}
@@ -2,94 +2,89 @@ package service
 
 import (
 	"bytes"
-	"context"
 	"embed"
-	"errors"
 	"html/template"
-	"log"
-	"net/smtp"
-	"os"
+	"strings"
+	textTemplate "text/template"
 )
 
-type smtpConfig struct {
-	User    string
-	Pass    string
-	Host    string
-	Port    string
+const DefaultLocale = "en"
+
+// Locales that have a translation in the templates directory
+var SupportedLocales = []string{"en", "id"}
+
+// EmailConfig holds the settings used to build links in outgoing emails
+type EmailConfig struct {
 	AppHost string
 	AppPort string
 	Secret  string
-	IsSend  bool
 }
 
-func LoadSMTPConfig() (smtpConfig, error) {
-	cfg := smtpConfig{
-		User:    os.Getenv("SMTP_EMAIL"),
-		Pass:    os.Getenv("SMTP_PASSWORD"),
-		Host:    os.Getenv("SMTP_HOST"),
-		Port:    os.Getenv("SMTP_PORT"),
-		AppHost: os.Getenv("APP_HOST"),
-		AppPort: os.Getenv("APP_PORT"),
-		Secret:  os.Getenv("EMAIL_SECRET_KEY"),
-		IsSend:  false,
-	}
-
-	if cfg.User == "" || cfg.Pass == "" || cfg.Host == "" ||
-		cfg.Port == "" || cfg.AppHost == "" || cfg.AppPort == "" {
-		return cfg, errors.New("missing smtp configuration")
-	}
-
-	return cfg, nil
-}
-
-//go:embed templates/*.html
+//go:embed templates/*/*.html templates/*/*.txt
 var templateFS embed.FS
 
 type verificationData struct {
 	VerifyURL string
 }
 
-func renderVerificationEmail(verifyURL string) (string, error) {
-	tmpl, err := template.ParseFS(templateFS, "templates/verification.html")
-	if err != nil {
-		return "", err
-	}
+// RenderedEmail is a localized email ready to be queued
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, verificationData{VerifyURL: verifyURL}); err != nil {
-		return "", err
+// NormalizeLocale returns the supported locale matching a tag such as "id-ID", or the default locale
+func NormalizeLocale(locale string) string {
+	language := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(language, "-_"); i != -1 {
+		language = language[:i]
 	}
 
-	return buf.String(), nil
+	for _, supported := range SupportedLocales {
+		if language == supported {
+			return supported
+		}
+	}
+	return DefaultLocale
 }
 
-func (cfg *smtpConfig) sendHTML(ctx context.Context, to, subject, htmlBody string) error {
-	auth := smtp.PlainAuth("", cfg.User, cfg.Pass, cfg.Host)
+// renderEmail renders templates/<locale>/<name>.txt and .html. The text template
+// also defines the "subject" block so every translation lives in one directory.
+func renderEmail(locale string, name string, data any) (*RenderedEmail, error) {
+	dir := "templates/" + NormalizeLocale(locale) + "/"
+
+	textTmpl, err := textTemplate.ParseFS(templateFS, dir+name+".txt")
+	if err != nil {
+		return nil, err
+	}
 
-	msg := []byte(
-		"From: " + cfg.User + "\r\n" +
-			"To: " + to + "\r\n" +
-			"Subject: " + subject + "\r\n" +
-			"MIME-Version: 1.0\r\n" +
-			"Content-Type: text/html; charset=UTF-8\r\n\r\n" +
-			htmlBody,
-	)
+	var subject, text bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
 
-	if !cfg.IsSend {
-		return nil
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return nil, err
 	}
 
-	done := make(chan error, 1)
-	go func() {
-		done <- smtp.SendMail(cfg.Host+":"+cfg.Port, auth, cfg.User, []string{to}, msg)
-	}()
-
-	select {
-	case <-ctx.Done():
-		log.Println("Email send cancelled")
-		return ctx.Err()
-	case err := <-done:
-		log.Println("Email send finished")
-		return err
+	htmlTmpl, err := template.ParseFS(templateFS, dir+name+".html")
+	if err != nil {
+		return nil, err
 	}
+
+	var html bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func renderVerificationEmail(locale string, verifyURL string) (*RenderedEmail, error) {
+	return renderEmail(locale, "verification", verificationData{VerifyURL: verifyURL})
 }
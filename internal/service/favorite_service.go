@@ -7,25 +7,46 @@ import (
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/response"
 	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
 )
 
 type FavoriteService interface {
 	CreateFavorite(ctx context.Context, userId string, underwriterId string) (*response.AddFavoriteResponse, error)
 	GetFavorites(ctx context.Context, userId string) (*response.GetFavoritesResponse, error)
 	RemoveFavorite(ctx context.Context, userId string, underwriterCode string) (*response.RemoveFavoriteResponse, error)
+	ImportFavorites(ctx context.Context, userId string, underwriterCodes []string) (*response.ImportResponse, error)
+	ExportFavorites(ctx context.Context, userId string) ([]string, error)
+	AddFavoritesBatch(ctx context.Context, userId string, underwriterCodes []string) (*response.BatchResponse, error)
+	RemoveFavoritesBatch(ctx context.Context, userId string, underwriterCodes []string) (*response.BatchResponse, error)
 }
 
 type FavoriteServiceImpl struct {
-	Repository repository.FavoriteRepository
+	Repository            repository.FavoriteRepository
+	UnderwriterRepository repository.UnderwriterRepository
 }
 
-func NewFavoriteService(repository repository.FavoriteRepository) FavoriteService {
+func NewFavoriteService(repository repository.FavoriteRepository, underwriterRepository repository.UnderwriterRepository) FavoriteService {
 	return &FavoriteServiceImpl{
-		Repository: repository,
+		Repository:            repository,
+		UnderwriterRepository: underwriterRepository,
 	}
 }
 
 func (service *FavoriteServiceImpl) CreateFavorite(ctx context.Context, userId string, underwriterId string) (*response.AddFavoriteResponse, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.CreateFavorite")
+	defer span.End()
+
+	underwriterId = strings.ToUpper(strings.TrimSpace(underwriterId))
+	exists, err := service.UnderwriterRepository.Exists(ctx, underwriterId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, domainerr.ErrUnderwriterNotFound
+	}
+
 	favorite := &entity.Favorite{
 		UserID:        userId,
 		UnderwriterID: underwriterId,
@@ -43,6 +64,9 @@ func (service *FavoriteServiceImpl) CreateFavorite(ctx context.Context, userId s
 }
 
 func (service *FavoriteServiceImpl) GetFavorites(ctx context.Context, userId string) (*response.GetFavoritesResponse, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.GetFavorites")
+	defer span.End()
+
 	favoriteData, err := service.Repository.GetFavorites(userId, ctx)
 	if err != nil {
 		return nil, err
@@ -60,7 +84,10 @@ func (service *FavoriteServiceImpl) GetFavorites(ctx context.Context, userId str
 }
 
 func (service *FavoriteServiceImpl) RemoveFavorite(ctx context.Context, userId string, underwriterCode string) (*response.RemoveFavoriteResponse, error) {
-	if err := service.Repository.RemoveFavorite(userId, underwriterCode, ctx); err != nil {
+	ctx, span := tracing.Start(ctx, "FavoriteService.RemoveFavorite")
+	defer span.End()
+
+	if err := service.Repository.RemoveFavorite(userId, strings.ToUpper(underwriterCode), ctx); err != nil {
 		return nil, err
 	}
 
@@ -70,3 +97,99 @@ func (service *FavoriteServiceImpl) RemoveFavorite(ctx context.Context, userId s
 
 	return response, nil
 }
+
+// ImportFavorites checks every code against the underwriter catalog and inserts the known ones together
+func (service *FavoriteServiceImpl) ImportFavorites(ctx context.Context, userId string, underwriterCodes []string) (*response.ImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ImportFavorites")
+	defer span.End()
+
+	rows, err := service.addFavoriteRows(ctx, underwriterCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := service.Repository.AddFavoritesBatch(userId, pendingBulkCodes(rows), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return finishImport(rows, inserted, domainerr.ErrFavoritesDuplicate), nil
+}
+
+func (service *FavoriteServiceImpl) AddFavoritesBatch(ctx context.Context, userId string, underwriterCodes []string) (*response.BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.AddFavoritesBatch")
+	defer span.End()
+
+	rows, err := service.addFavoriteRows(ctx, underwriterCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := service.Repository.AddFavoritesBatch(userId, pendingBulkCodes(rows), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return finishBatch(rows, added, response.BatchItemAdded, domainerr.ErrFavoritesDuplicate), nil
+}
+
+func (service *FavoriteServiceImpl) RemoveFavoritesBatch(ctx context.Context, userId string, underwriterCodes []string) (*response.BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.RemoveFavoritesBatch")
+	defer span.End()
+
+	rows, err := newBulkRows(underwriterCodes, normalizeUnderwriter)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := service.Repository.RemoveFavoritesBatch(userId, pendingBulkCodes(rows), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return finishBatch(rows, removed, response.BatchItemRemoved, domainerr.ErrFavoritesNotFound), nil
+}
+
+// addFavoriteRows builds the rows of an import or batch add and fails the codes missing from the catalog
+func (service *FavoriteServiceImpl) addFavoriteRows(ctx context.Context, underwriterCodes []string) ([]response.ImportRowResult, error) {
+	rows, err := newBulkRows(underwriterCodes, normalizeUnderwriter)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := service.UnderwriterRepository.GetUnderwriters(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(catalog))
+	for _, underwriter := range catalog {
+		known[underwriter.ID] = true
+	}
+
+	messages := make(map[string]string)
+	for _, code := range pendingBulkCodes(rows) {
+		if !known[code] {
+			messages[code] = domainerr.ErrUnderwriterNotFound.Error()
+		}
+	}
+	failBulkRows(rows, messages)
+
+	return rows, nil
+}
+
+func (service *FavoriteServiceImpl) ExportFavorites(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "FavoriteService.ExportFavorites")
+	defer span.End()
+
+	favorites, err := service.Repository.GetFavorites(userId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(favorites))
+	for _, favorite := range favorites {
+		codes = append(codes, favorite.ID)
+	}
+	return codes, nil
+}
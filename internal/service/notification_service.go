@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
+)
+
+var deviceTokenPattern = regexp.MustCompile(`^[0-9a-fA-F]{64,200}$`)
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userId string, unreadOnly bool) (*response.GetNotificationsResponse, error)
+	MarkRead(ctx context.Context, userId string, notificationId string, read bool) (*response.MarkNotificationResponse, error)
+	MarkAllRead(ctx context.Context, userId string) (*response.MarkNotificationResponse, error)
+	GetPreferences(ctx context.Context, userId string) (*response.GetNotificationPreferencesResponse, error)
+	UpdatePreference(ctx context.Context, userId string, channel string, request request.UpdateNotificationPreferenceRequest) (*response.UpdateNotificationPreferenceResponse, error)
+}
+
+type NotificationServiceImpl struct {
+	Repository repository.NotificationRepository
+}
+
+func NewNotificationService(repository repository.NotificationRepository) NotificationService {
+	return &NotificationServiceImpl{
+		Repository: repository,
+	}
+}
+
+func (service *NotificationServiceImpl) GetNotifications(ctx context.Context, userId string, unreadOnly bool) (*response.GetNotificationsResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetNotifications")
+	defer span.End()
+
+	notifications, err := service.Repository.GetNotifications(ctx, userId, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	unread := 0
+	for _, notification := range notifications {
+		if !notification.Read {
+			unread++
+		}
+	}
+
+	response := &response.GetNotificationsResponse{
+		Message: "Notifications retrieved successfully",
+		Unread:  unread,
+		Data:    notifications,
+	}
+	return response, nil
+}
+
+func (service *NotificationServiceImpl) MarkRead(ctx context.Context, userId string, notificationId string, read bool) (*response.MarkNotificationResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkRead")
+	defer span.End()
+
+	if err := service.Repository.SetNotificationRead(ctx, userId, notificationId, read); err != nil {
+		return nil, err
+	}
+
+	message := "Notification marked as read"
+	if !read {
+		message = "Notification marked as unread"
+	}
+
+	response := &response.MarkNotificationResponse{
+		Message: message,
+	}
+	return response, nil
+}
+
+func (service *NotificationServiceImpl) MarkAllRead(ctx context.Context, userId string) (*response.MarkNotificationResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	if _, err := service.Repository.MarkAllNotificationsRead(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	response := &response.MarkNotificationResponse{
+		Message: "All notifications marked as read",
+	}
+	return response, nil
+}
+
+func (service *NotificationServiceImpl) GetPreferences(ctx context.Context, userId string) (*response.GetNotificationPreferencesResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetPreferences")
+	defer span.End()
+
+	preferences, err := service.Repository.GetPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetNotificationPreferencesResponse{
+		Message: "Notification preferences retrieved successfully",
+		Data:    preferences,
+	}
+	return response, nil
+}
+
+func (service *NotificationServiceImpl) UpdatePreference(ctx context.Context, userId string, channel string, request request.UpdateNotificationPreferenceRequest) (*response.UpdateNotificationPreferenceResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.UpdatePreference")
+	defer span.End()
+
+	preference := entity.NotificationPreference{
+		UserID:  userId,
+		Channel: channel,
+		Enabled: *request.Enabled,
+		Target:  request.Target,
+	}
+
+	if err := validatePreferenceTarget(preference); err != nil {
+		return nil, err
+	}
+
+	// Alerts are only emailed to the verified account address, another
+	// address would receive them without ever confirming it
+	if preference.Channel == entity.ChannelEmail && preference.Target != "" {
+		accountEmail, err := service.Repository.GetUserEmail(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(preference.Target, accountEmail) {
+			return nil, domainerr.ErrNotificationEmailNotOwned
+		}
+	}
+
+	if err := service.Repository.UpsertPreference(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	response := &response.UpdateNotificationPreferenceResponse{
+		Message: "Notification preference updated successfully",
+		Data:    preference,
+	}
+	return response, nil
+}
+
+func validatePreferenceTarget(preference entity.NotificationPreference) error {
+	switch preference.Channel {
+	case entity.ChannelInApp:
+		return nil
+
+	case entity.ChannelEmail:
+		// An empty target falls back to the account email
+		if preference.Target == "" {
+			return nil
+		}
+		if _, err := mail.ParseAddress(preference.Target); err != nil {
+			return domainerr.ErrNotificationTargetInvalid
+		}
+		return nil
+
+	case entity.ChannelWebhook:
+		if preference.Target == "" {
+			if preference.Enabled {
+				return domainerr.ErrNotificationTargetRequired
+			}
+			return nil
+		}
+		// Only public HTTPS endpoints, the worker posts from inside the network.
+		// Hostnames are checked again on every delivery when they are resolved.
+		parsed, err := url.Parse(preference.Target)
+		if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+			return domainerr.ErrNotificationTargetInvalid
+		}
+		if strings.EqualFold(parsed.Hostname(), "localhost") || strings.HasSuffix(strings.ToLower(parsed.Hostname()), ".localhost") {
+			return domainerr.ErrNotificationTargetInvalid
+		}
+		if ip, err := netip.ParseAddr(parsed.Hostname()); err == nil && !notifier.IsPublicAddress(ip) {
+			return domainerr.ErrNotificationTargetInvalid
+		}
+		return nil
+
+	case entity.ChannelAPNs:
+		if preference.Target == "" {
+			if preference.Enabled {
+				return domainerr.ErrNotificationTargetRequired
+			}
+			return nil
+		}
+		if !deviceTokenPattern.MatchString(preference.Target) {
+			return domainerr.ErrNotificationTargetInvalid
+		}
+		return nil
+
+	default:
+		return domainerr.ErrNotificationChannelInvalid
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"sort"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+
+	"github.com/shopspring/decimal"
+)
+
+// Derived amounts such as average costs are rounded to this many decimal places
+const ledgerScale = 4
+
+// lot is a block of shares bought together, used by the FIFO cost method
+type lot struct {
+	quantity decimal.Decimal
+	cost     decimal.Decimal
+}
+
+// position is the state of one stock while its ledger is replayed
+type position struct {
+	stock     string
+	quantity  decimal.Decimal
+	cost      decimal.Decimal
+	lots      []lot
+	realized  decimal.Decimal
+	dividends decimal.Decimal
+}
+
+// sortLedger orders transactions the way they are replayed
+func sortLedger(ledger []entity.PortfolioTransaction) {
+	sort.SliceStable(ledger, func(i, j int) bool {
+		if !ledger[i].TradedAt.Equal(ledger[j].TradedAt) {
+			return ledger[i].TradedAt.Before(ledger[j].TradedAt)
+		}
+		return ledger[i].CreatedAt.Before(ledger[j].CreatedAt)
+	})
+}
+
+// replayLedger derives the position of every stock in the ledger, in the order
+// the stocks first appear. It fails when a sell exceeds the shares held.
+func replayLedger(costMethod string, ledger []entity.PortfolioTransaction) ([]*position, error) {
+	sortLedger(ledger)
+
+	positions := []*position{}
+	byStock := map[string]*position{}
+	for _, transaction := range ledger {
+		p, ok := byStock[transaction.Stock]
+		if !ok {
+			p = &position{stock: transaction.Stock}
+			byStock[transaction.Stock] = p
+			positions = append(positions, p)
+		}
+
+		if err := p.apply(costMethod, transaction); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+func (p *position) apply(costMethod string, transaction entity.PortfolioTransaction) error {
+	switch transaction.Type {
+	case entity.TransactionBuy:
+		cost := transaction.Quantity.Mul(transaction.Price).Add(transaction.Fee)
+		p.quantity = p.quantity.Add(transaction.Quantity)
+		p.cost = p.cost.Add(cost)
+		if costMethod == entity.CostMethodFIFO {
+			p.lots = append(p.lots, lot{quantity: transaction.Quantity, cost: cost})
+		}
+
+	case entity.TransactionSell:
+		if transaction.Quantity.GreaterThan(p.quantity) {
+			return domainerr.ErrPortfolioInsufficientShares
+		}
+
+		var basis decimal.Decimal
+		switch {
+		case costMethod == entity.CostMethodFIFO:
+			basis = p.consumeLots(transaction.Quantity)
+		case transaction.Quantity.Equal(p.quantity):
+			basis = p.cost
+		default:
+			basis = p.cost.Mul(transaction.Quantity).Div(p.quantity)
+		}
+
+		proceeds := transaction.Quantity.Mul(transaction.Price).Sub(transaction.Fee)
+		p.realized = p.realized.Add(proceeds.Sub(basis))
+		p.quantity = p.quantity.Sub(transaction.Quantity)
+		p.cost = p.cost.Sub(basis)
+
+	case entity.TransactionDividend:
+		p.dividends = p.dividends.Add(transaction.Amount)
+
+	case entity.TransactionSplit:
+		// A split changes the share count but not the amount paid
+		p.quantity = p.quantity.Mul(transaction.Ratio)
+		for i := range p.lots {
+			p.lots[i].quantity = p.lots[i].quantity.Mul(transaction.Ratio)
+		}
+	}
+	return nil
+}
+
+// consumeLots removes quantity shares from the oldest lots and returns their cost
+func (p *position) consumeLots(quantity decimal.Decimal) decimal.Decimal {
+	basis := decimal.Zero
+	for quantity.IsPositive() && len(p.lots) > 0 {
+		oldest := &p.lots[0]
+		if oldest.quantity.LessThanOrEqual(quantity) {
+			basis = basis.Add(oldest.cost)
+			quantity = quantity.Sub(oldest.quantity)
+			p.lots = p.lots[1:]
+			continue
+		}
+
+		part := oldest.cost.Mul(quantity).Div(oldest.quantity)
+		basis = basis.Add(part)
+		oldest.cost = oldest.cost.Sub(part)
+		oldest.quantity = oldest.quantity.Sub(quantity)
+		quantity = decimal.Zero
+	}
+	return basis
+}
+
+func (p *position) holding() entity.Holding {
+	averageCost := decimal.Zero
+	if p.quantity.IsPositive() {
+		averageCost = p.cost.Div(p.quantity)
+	}
+
+	return entity.Holding{
+		Stock:       p.stock,
+		Quantity:    p.quantity,
+		AverageCost: averageCost.Round(ledgerScale),
+		CostBasis:   p.cost.Round(ledgerScale),
+		RealizedPnL: p.realized.Round(ledgerScale),
+		Dividends:   p.dividends,
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"stock_backend/internal/client"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type PortfolioService interface {
+	CreatePortfolio(ctx context.Context, userId string, request request.CreatePortfolioRequest) (*response.CreatePortfolioResponse, error)
+	GetPortfolios(ctx context.Context, userId string) (*response.GetPortfoliosResponse, error)
+	GetPortfolio(ctx context.Context, userId string, portfolioId string) (*response.GetPortfolioResponse, error)
+	UpdatePortfolio(ctx context.Context, userId string, portfolioId string, request request.UpdatePortfolioRequest) (*response.UpdatePortfolioResponse, error)
+	DeletePortfolio(ctx context.Context, userId string, portfolioId string) (*response.DeletePortfolioResponse, error)
+	CreateTransaction(ctx context.Context, userId string, portfolioId string, request request.CreateTransactionRequest) (*response.CreateTransactionResponse, error)
+	GetTransactions(ctx context.Context, userId string, portfolioId string, stock string) (*response.GetTransactionsResponse, error)
+	DeleteTransaction(ctx context.Context, userId string, portfolioId string, transactionId string) (*response.DeleteTransactionResponse, error)
+	GetHoldings(ctx context.Context, userId string, portfolioId string) (*response.GetHoldingsResponse, error)
+	GetSummary(ctx context.Context, userId string, portfolioId string) (*response.GetPortfolioSummaryResponse, error)
+}
+
+type PortfolioServiceImpl struct {
+	Repository  repository.PortfolioRepository
+	stockClient client.StockClient
+}
+
+func NewPortfolioService(repository repository.PortfolioRepository, stockClient client.StockClient) PortfolioService {
+	return &PortfolioServiceImpl{
+		Repository:  repository,
+		stockClient: stockClient,
+	}
+}
+
+func (service *PortfolioServiceImpl) CreatePortfolio(ctx context.Context, userId string, request request.CreatePortfolioRequest) (*response.CreatePortfolioResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.CreatePortfolio")
+	defer span.End()
+
+	costMethod := request.CostMethod
+	if costMethod == "" {
+		costMethod = entity.CostMethodAverage
+	}
+
+	portfolio := &entity.Portfolio{
+		ID:         uuid.New(),
+		UserID:     userId,
+		Name:       strings.TrimSpace(request.Name),
+		CostMethod: costMethod,
+	}
+
+	if err := service.Repository.CreatePortfolio(ctx, portfolio); err != nil {
+		return nil, err
+	}
+
+	response := &response.CreatePortfolioResponse{
+		Message: "Portfolio created successfully",
+		Data:    *portfolio,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) GetPortfolios(ctx context.Context, userId string) (*response.GetPortfoliosResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.GetPortfolios")
+	defer span.End()
+
+	portfolios, err := service.Repository.GetPortfolios(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetPortfoliosResponse{
+		Message: "Portfolios retrieved successfully",
+		Data:    portfolios,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) GetPortfolio(ctx context.Context, userId string, portfolioId string) (*response.GetPortfolioResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.GetPortfolio")
+	defer span.End()
+
+	portfolio, err := service.Repository.GetPortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetPortfolioResponse{
+		Message: "Portfolio retrieved successfully",
+		Data:    *portfolio,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) UpdatePortfolio(ctx context.Context, userId string, portfolioId string, request request.UpdatePortfolioRequest) (*response.UpdatePortfolioResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.UpdatePortfolio")
+	defer span.End()
+
+	name := request.Name
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		name = &trimmed
+	}
+
+	// Holdings are derived on read, so switching the cost method recomputes them from the ledger
+	portfolio, err := service.Repository.UpdatePortfolio(ctx, userId, portfolioId, name, request.CostMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.UpdatePortfolioResponse{
+		Message: "Portfolio updated successfully",
+		Data:    *portfolio,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) DeletePortfolio(ctx context.Context, userId string, portfolioId string) (*response.DeletePortfolioResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.DeletePortfolio")
+	defer span.End()
+
+	if err := service.Repository.DeletePortfolio(ctx, userId, portfolioId); err != nil {
+		return nil, err
+	}
+
+	response := &response.DeletePortfolioResponse{
+		Message: "Portfolio deleted successfully",
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) CreateTransaction(ctx context.Context, userId string, portfolioId string, request request.CreateTransactionRequest) (*response.CreateTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.CreateTransaction")
+	defer span.End()
+
+	portfolio, err := service.Repository.GetPortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := newTransaction(portfolio.ID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	check := func(ledger []entity.PortfolioTransaction) error {
+		_, err := replayLedger(portfolio.CostMethod, ledger)
+		return err
+	}
+
+	if err := service.Repository.AddTransaction(ctx, userId, transaction, check); err != nil {
+		return nil, err
+	}
+
+	response := &response.CreateTransactionResponse{
+		Message: "Transaction recorded successfully",
+		Data:    *transaction,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) GetTransactions(ctx context.Context, userId string, portfolioId string, stock string) (*response.GetTransactionsResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.GetTransactions")
+	defer span.End()
+
+	if _, err := service.Repository.GetPortfolio(ctx, userId, portfolioId); err != nil {
+		return nil, err
+	}
+
+	if stock != "" {
+		symbol, err := helper.NormalizeSymbol(stock)
+		if err != nil {
+			return nil, err
+		}
+		stock = symbol.String()
+	}
+
+	transactions, err := service.Repository.GetTransactions(ctx, portfolioId, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetTransactionsResponse{
+		Message: "Transactions retrieved successfully",
+		Data:    transactions,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) DeleteTransaction(ctx context.Context, userId string, portfolioId string, transactionId string) (*response.DeleteTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.DeleteTransaction")
+	defer span.End()
+
+	portfolio, err := service.Repository.GetPortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing a buy must not leave a later sell without shares
+	check := func(ledger []entity.PortfolioTransaction) error {
+		_, err := replayLedger(portfolio.CostMethod, ledger)
+		return err
+	}
+
+	if err := service.Repository.DeleteTransaction(ctx, userId, portfolioId, transactionId, check); err != nil {
+		return nil, err
+	}
+
+	response := &response.DeleteTransactionResponse{
+		Message: "Transaction deleted successfully",
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) GetHoldings(ctx context.Context, userId string, portfolioId string) (*response.GetHoldingsResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.GetHoldings")
+	defer span.End()
+
+	_, holdings, err := service.getHoldings(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetHoldingsResponse{
+		Message: "Holdings retrieved successfully",
+		Data:    holdings,
+	}
+	return response, nil
+}
+
+func (service *PortfolioServiceImpl) GetSummary(ctx context.Context, userId string, portfolioId string) (*response.GetPortfolioSummaryResponse, error) {
+	ctx, span := tracing.Start(ctx, "PortfolioService.GetSummary")
+	defer span.End()
+
+	portfolio, holdings, err := service.getHoldings(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := entity.PortfolioSummary{
+		PortfolioID: portfolio.ID,
+		CostMethod:  portfolio.CostMethod,
+	}
+	for _, holding := range holdings {
+		summary.RealizedPnL = summary.RealizedPnL.Add(holding.RealizedPnL)
+		summary.Dividends = summary.Dividends.Add(holding.Dividends)
+		if !holding.Quantity.IsPositive() {
+			continue
+		}
+
+		summary.OpenPositions++
+		summary.CostBasis = summary.CostBasis.Add(holding.CostBasis)
+		if holding.MarketValue == nil {
+			summary.MissingQuotes++
+			continue
+		}
+		summary.MarketValue = summary.MarketValue.Add(*holding.MarketValue)
+		summary.UnrealizedPnL = summary.UnrealizedPnL.Add(*holding.UnrealizedPnL)
+	}
+	summary.TotalReturn = summary.RealizedPnL.Add(summary.UnrealizedPnL).Add(summary.Dividends)
+
+	response := &response.GetPortfolioSummaryResponse{
+		Message: "Portfolio summary retrieved successfully",
+		Data:    summary,
+	}
+	return response, nil
+}
+
+// getHoldings replays the ledger of a portfolio and values its open positions at their last price
+func (service *PortfolioServiceImpl) getHoldings(ctx context.Context, userId string, portfolioId string) (*entity.Portfolio, []entity.Holding, error) {
+	portfolio, err := service.Repository.GetPortfolio(ctx, userId, portfolioId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ledger, err := service.Repository.GetTransactions(ctx, portfolioId, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positions, err := replayLedger(portfolio.CostMethod, ledger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holdings := make([]entity.Holding, len(positions))
+	open := map[string]int{}
+	stocks := []string{}
+	for i, position := range positions {
+		holdings[i] = position.holding()
+		if position.quantity.IsPositive() {
+			open[position.stock] = i
+			stocks = append(stocks, position.stock)
+		}
+	}
+
+	if len(stocks) == 0 {
+		return portfolio, holdings, nil
+	}
+
+	for _, result := range service.stockClient.GetQuotes(ctx, stocks) {
+		holding := &holdings[open[result.Stock]]
+		if result.Err != nil {
+			holding.QuoteError = quoteErrorMessage(result.Err)
+			continue
+		}
+
+		lastPrice := decimal.NewFromFloat(result.Quote.LastPrice)
+		marketValue := holding.Quantity.Mul(lastPrice).Round(ledgerScale)
+		unrealized := marketValue.Sub(holding.CostBasis)
+		holding.LastPrice = &lastPrice
+		holding.MarketValue = &marketValue
+		holding.UnrealizedPnL = &unrealized
+	}
+
+	return portfolio, holdings, nil
+}
+
+// newTransaction checks the amounts a transaction type needs and drops the ones it ignores
+func newTransaction(portfolioId uuid.UUID, request request.CreateTransactionRequest) (*entity.PortfolioTransaction, error) {
+	symbol, err := helper.NormalizeSymbol(request.Stock)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tradedAt := now
+	if request.TradedAt != nil {
+		if request.TradedAt.After(now) {
+			return nil, domainerr.ErrPortfolioTradedAtInvalid
+		}
+		tradedAt = *request.TradedAt
+	}
+
+	transaction := &entity.PortfolioTransaction{
+		ID:          uuid.New(),
+		PortfolioID: portfolioId,
+		Stock:       symbol.String(),
+		Type:        request.Type,
+		Note:        strings.TrimSpace(request.Note),
+		TradedAt:    tradedAt,
+		CreatedAt:   now,
+	}
+
+	switch request.Type {
+	case entity.TransactionBuy, entity.TransactionSell:
+		if !request.Quantity.IsPositive() {
+			return nil, domainerr.ErrPortfolioQuantityInvalid
+		}
+
+		if !request.Price.IsPositive() {
+			return nil, domainerr.ErrPortfolioPriceInvalid
+		}
+
+		if request.Fee.IsNegative() {
+			return nil, domainerr.ErrPortfolioFeeInvalid
+		}
+		transaction.Quantity = request.Quantity
+		transaction.Price = request.Price
+		transaction.Fee = request.Fee
+
+	case entity.TransactionDividend:
+		if !request.Amount.IsPositive() {
+			return nil, domainerr.ErrPortfolioAmountInvalid
+		}
+		transaction.Amount = request.Amount
+
+	case entity.TransactionSplit:
+		if !request.Ratio.IsPositive() || request.Ratio.Equal(decimal.NewFromInt(1)) {
+			return nil, domainerr.ErrPortfolioRatioInvalid
+		}
+		transaction.Ratio = request.Ratio
+	}
+
+	return transaction, nil
+}
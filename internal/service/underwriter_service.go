@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"stock_backend/internal/client"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
+)
+
+var underwriterCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type UnderwriterService interface {
+	GetUnderwriters(ctx context.Context, search string) (*response.GetUnderwritersResponse, error)
+	SyncUnderwriters(ctx context.Context) (*response.SyncUnderwritersResponse, error)
+}
+
+type UnderwriterServiceImpl struct {
+	Repository  repository.UnderwriterRepository
+	StockClient client.StockClient
+}
+
+func NewUnderwriterService(repository repository.UnderwriterRepository, stockClient client.StockClient) UnderwriterService {
+	return &UnderwriterServiceImpl{
+		Repository:  repository,
+		StockClient: stockClient,
+	}
+}
+
+func (service *UnderwriterServiceImpl) GetUnderwriters(ctx context.Context, search string) (*response.GetUnderwritersResponse, error) {
+	ctx, span := tracing.Start(ctx, "UnderwriterService.GetUnderwriters")
+	defer span.End()
+
+	underwriters, err := service.Repository.GetUnderwriters(ctx, strings.TrimSpace(search))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.GetUnderwritersResponse{
+		Message: "Underwriters retrieved successfully",
+		Data:    underwriters,
+	}
+	return response, nil
+}
+
+// SyncUnderwriters copies the catalog of the stock service into the local table
+func (service *UnderwriterServiceImpl) SyncUnderwriters(ctx context.Context) (*response.SyncUnderwritersResponse, error) {
+	ctx, span := tracing.Start(ctx, "UnderwriterService.SyncUnderwriters")
+	defer span.End()
+
+	remote, err := service.StockClient.GetUnderwriters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	underwriters := make([]entity.Underwriter, 0, len(remote))
+	for _, underwriter := range remote {
+		if !underwriterCodePattern.MatchString(underwriter.ID) || underwriter.Name == "" {
+			logging.Warn(ctx, "skipping invalid underwriter", "underwriter_id", underwriter.ID)
+			continue
+		}
+		underwriters = append(underwriters, underwriter)
+	}
+
+	if err := service.Repository.UpsertUnderwriters(ctx, underwriters); err != nil {
+		return nil, err
+	}
+
+	response := &response.SyncUnderwritersResponse{
+		Message: fmt.Sprintf("Synced %d underwriters", len(underwriters)),
+		Synced:  len(underwriters),
+	}
+	return response, nil
+}
@@ -3,13 +3,13 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
 	"stock_backend/internal/entity"
 	"stock_backend/internal/helper"
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/request"
 	"stock_backend/internal/model/response"
 	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
@@ -28,18 +28,21 @@ type UserService interface {
 type UserServiceImpl struct {
 	Repository repository.UserRepository
 	JwtSecret  string
-	Smtp       smtpConfig
+	Email      EmailConfig
 }
 
-func NewUserService(repository repository.UserRepository, jwtSecret string, smtp smtpConfig) UserService {
+func NewUserService(repository repository.UserRepository, jwtSecret string, email EmailConfig) UserService {
 	return &UserServiceImpl{
 		Repository: repository,
 		JwtSecret:  jwtSecret,
-		Smtp:       smtp,
+		Email:      email,
 	}
 }
 
 func (service *UserServiceImpl) Login(ctx context.Context, request request.LoginRequest) (*response.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
 	user, err := service.Repository.GetUser(request.Email, ctx)
 	if err != nil {
 		return nil, err
@@ -74,6 +77,9 @@ func (service *UserServiceImpl) Login(ctx context.Context, request request.Login
 }
 
 func (service *UserServiceImpl) Register(ctx context.Context, request request.RegisterRequest) (*response.RegisterResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Register")
+	defer span.End()
+
 	hash, err := bcrypt.GenerateFromPassword(
 		[]byte(request.Password),
 		bcrypt.DefaultCost,
@@ -88,27 +94,30 @@ func (service *UserServiceImpl) Register(ctx context.Context, request request.Re
 		Username: request.Username,
 		Email:    request.Email,
 		Password: string(hash),
+		Locale:   NormalizeLocale(request.Locale),
 	}
 
-	// var createdUser *entity.User
-	if _, err := service.Repository.Create(user, ctx); err != nil {
+	token, err := helper.GenerateJWT(user.ID.String(), user.Email, user.Role, service.Email.Secret)
+	if err != nil {
 		return nil, err
 	}
 
-	token, err := helper.GenerateJWT(user.ID.String(), user.Email, user.Role, service.Smtp.Secret)
+	verifyURL := fmt.Sprintf("http://%s:%s/api/v1/auth/verify?token=%s", service.Email.AppHost, service.Email.AppPort, token)
+	rendered, err := renderVerificationEmail(user.Locale, verifyURL)
 	if err != nil {
 		return nil, err
 	}
 
-	verifyURL := fmt.Sprintf("http://%s:%s/api/v1/auth/verify?token=%s", service.Smtp.AppHost, service.Smtp.AppPort, token)
-	htmlBody, err := renderVerificationEmail(verifyURL)
-	if err != nil {
-		return nil, err
+	// The email is sent by the outbox dispatcher once the user is committed
+	verificationEmail := &entity.EmailOutbox{
+		Recipient: user.Email,
+		Subject:   rendered.Subject,
+		TextBody:  rendered.Text,
+		HTMLBody:  rendered.HTML,
 	}
 
-	// Run this code if email verification is required and SMTP server is configured
-	if err := service.Smtp.sendHTML(ctx, user.Email, "Verify your Stock App account", htmlBody); err != nil {
-		log.Printf("[ERROR] error email: %v", err)
+	if _, err := service.Repository.Create(user, verificationEmail, ctx); err != nil {
+		return nil, err
 	}
 
 	response := &response.RegisterResponse{
@@ -119,7 +128,10 @@ func (service *UserServiceImpl) Register(ctx context.Context, request request.Re
 }
 
 func (service *UserServiceImpl) VerifyUser(ctx context.Context, tokenString string) (*response.VerifyResponse, error) {
-	token, err := helper.ValidateJWT(tokenString, service.Smtp.Secret)
+	ctx, span := tracing.Start(ctx, "UserService.VerifyUser")
+	defer span.End()
+
+	token, err := helper.ValidateJWT(tokenString, service.Email.Secret)
 	if err != nil {
 		return nil, domainerr.ErrInvalidToken
 	}
@@ -146,6 +158,9 @@ func (service *UserServiceImpl) VerifyUser(ctx context.Context, tokenString stri
 }
 
 func (service *UserServiceImpl) Logout(ctx context.Context, userId string) (*response.LogoutResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Logout")
+	defer span.End()
+
 	if err := service.Repository.Logout(userId, ctx); err != nil {
 		return nil, err
 	}
@@ -158,6 +173,9 @@ func (service *UserServiceImpl) Logout(ctx context.Context, userId string) (*res
 }
 
 func (service *UserServiceImpl) DeleteUser(ctx context.Context, userId string) (*response.DeleteUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+
 	if err := service.Repository.DeleteUser(userId, ctx); err != nil {
 		return nil, err
 	}
@@ -170,6 +188,9 @@ func (service *UserServiceImpl) DeleteUser(ctx context.Context, userId string) (
 }
 
 func (service *UserServiceImpl) GetProfile(ctx context.Context, userId string) (*response.UserProfileResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetProfile")
+	defer span.End()
+
 	user, err := service.Repository.GetUserByID(userId, ctx)
 
 	if err != nil {
@@ -2,38 +2,62 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"stock_backend/internal/client"
+	"stock_backend/internal/entity"
 	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
 	"stock_backend/internal/model/response"
 	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
 )
 
+// Number of workers looking up stocks while validating an import or batch
+const stockValidationWorkers = 5
+
 type WatchlistService interface {
 	AddToWatchlist(ctx context.Context, userId string, stock string) (*response.AddWatchlistResponse, error)
 	RemoveFromWatchlist(ctx context.Context, userId string, stock string) (*response.RemoveWatchlistResponse, error)
-	GetWatchlist(ctx context.Context, userId string) (*response.GetWatchlistResponse, error)
+	GetWatchlist(ctx context.Context, userId string, filter entity.WatchlistFilter) (*response.GetWatchlistResponse, error)
+	GetWatchlistWithQuotes(ctx context.Context, userId string, filter entity.WatchlistFilter) (*response.GetWatchlistQuotesResponse, error)
+	UpdateWatchlistEntry(ctx context.Context, userId string, stock string, request request.UpdateWatchlistRequest) (*response.UpdateWatchlistResponse, error)
+	ImportWatchlist(ctx context.Context, userId string, stocks []string) (*response.ImportResponse, error)
+	ExportWatchlist(ctx context.Context, userId string) ([]string, error)
+	AddToWatchlistBatch(ctx context.Context, userId string, stocks []string) (*response.BatchResponse, error)
+	RemoveFromWatchlistBatch(ctx context.Context, userId string, stocks []string) (*response.BatchResponse, error)
 }
 
 type WatchlistServiceImpl struct {
-	Repository repository.WatchlistRepository
+	Repository  repository.WatchlistRepository
 	stockClient client.StockClient
 }
 
 func NewWatchlistService(repository repository.WatchlistRepository, stockClient client.StockClient) WatchlistService {
 	return &WatchlistServiceImpl{
-		Repository: repository,
+		Repository:  repository,
 		stockClient: stockClient,
 	}
 }
 
 func (service *WatchlistServiceImpl) AddToWatchlist(ctx context.Context, userId string, stock string) (*response.AddWatchlistResponse, error) {
-	if err := service.stockClient.GetStock(ctx, stock); err != nil {
+	ctx, span := tracing.Start(ctx, "WatchlistService.AddToWatchlist")
+	defer span.End()
+
+	stock, err := normalizeStock(stock)
+	if err != nil {
 		return nil, err
 	}
 
-	err := service.Repository.AddWatchlist(ctx, userId, stock)
-	if err != nil {
+	if _, err := service.stockClient.GetStock(ctx, stock); err != nil {
+		return nil, err
+	}
+
+	if err := service.Repository.AddWatchlist(ctx, userId, stock); err != nil {
 		return nil, err
 	}
 
@@ -45,6 +69,14 @@ func (service *WatchlistServiceImpl) AddToWatchlist(ctx context.Context, userId
 }
 
 func (service *WatchlistServiceImpl) RemoveFromWatchlist(ctx context.Context, userId string, stock string) (*response.RemoveWatchlistResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.RemoveFromWatchlist")
+	defer span.End()
+
+	stock, err := normalizeStock(stock)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := service.Repository.RemoveWatchlist(ctx, userId, stock); err != nil {
 		return nil, err
 	}
@@ -55,19 +87,256 @@ func (service *WatchlistServiceImpl) RemoveFromWatchlist(ctx context.Context, us
 	return response, nil
 }
 
-func (service *WatchlistServiceImpl) GetWatchlist(ctx context.Context, userId string) (*response.GetWatchlistResponse, error) {
-	watchlist, err := service.Repository.GetWatchlistByUserID(ctx, userId)
+func (service *WatchlistServiceImpl) GetWatchlist(ctx context.Context, userId string, filter entity.WatchlistFilter) (*response.GetWatchlistResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.GetWatchlist")
+	defer span.End()
+
+	watchlist, err := service.getEntries(ctx, userId, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(watchlist) == 0 {
-		return nil, domainerr.ErrWatchlistNotFound
-	}
-
 	response := &response.GetWatchlistResponse{
 		Message: "Watchlist retrieved successfully",
 		Stocks:  watchlist,
 	}
 	return response, nil
 }
+
+func (service *WatchlistServiceImpl) GetWatchlistWithQuotes(ctx context.Context, userId string, filter entity.WatchlistFilter) (*response.GetWatchlistQuotesResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.GetWatchlistWithQuotes")
+	defer span.End()
+
+	entries, err := service.getEntries(ctx, userId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		watchlist = append(watchlist, entry.Stock)
+	}
+
+	quotes := service.stockClient.GetQuotes(ctx, watchlist)
+	stocks := make([]response.WatchlistStockQuote, 0, len(quotes))
+	for _, result := range quotes {
+		item := response.WatchlistStockQuote{Stock: result.Stock}
+		if result.Err != nil {
+			item.QuoteError = quoteErrorMessage(result.Err)
+		} else {
+			item.Quote = &response.StockQuote{
+				LastPrice: result.Quote.LastPrice,
+				Change:    result.Quote.Change,
+				Volume:    result.Quote.Volume,
+			}
+		}
+		stocks = append(stocks, item)
+	}
+
+	response := &response.GetWatchlistQuotesResponse{
+		Message: "Watchlist retrieved successfully",
+		Stocks:  stocks,
+	}
+	return response, nil
+}
+
+func (service *WatchlistServiceImpl) getEntries(ctx context.Context, userId string, filter entity.WatchlistFilter) ([]entity.Watchlist, error) {
+	switch filter.Sort {
+	case "", entity.WatchlistSortAddedAt, entity.WatchlistSortAddedAtDesc, entity.WatchlistSortStock, entity.WatchlistSortStockDesc:
+	default:
+		return nil, domainerr.ErrWatchlistSortInvalid
+	}
+
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	entries, err := service.Repository.GetWatchlistEntries(ctx, userId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, domainerr.ErrWatchlistNotFound
+	}
+	return entries, nil
+}
+
+func (service *WatchlistServiceImpl) UpdateWatchlistEntry(ctx context.Context, userId string, stock string, request request.UpdateWatchlistRequest) (*response.UpdateWatchlistResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.UpdateWatchlistEntry")
+	defer span.End()
+
+	stock, err := normalizeStock(stock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zero clears a target, below that is never a price
+	for _, target := range []*decimal.Decimal{request.TargetBuy, request.TargetSell} {
+		if target != nil && target.IsNegative() {
+			return nil, domainerr.ErrWatchlistTargetNegative
+		}
+	}
+
+	update := entity.WatchlistUpdate{
+		Note:       request.Note,
+		TargetBuy:  request.TargetBuy,
+		TargetSell: request.TargetSell,
+		Tags:       normalizeTags(request.Tags),
+	}
+
+	entry, err := service.Repository.UpdateWatchlistEntry(ctx, userId, stock, update)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.UpdateWatchlistResponse{
+		Message: fmt.Sprintf("Successfully updated %s in watchlist", entry.Stock),
+		Data:    *entry,
+	}
+	return response, nil
+}
+
+// normalizeTags lowercases and deduplicates tags while keeping their order.
+// A nil slice stays nil so the tags are left unchanged.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// ImportWatchlist validates every stock with the stock service and inserts the
+// valid ones together. Rows are reported individually so one bad code never
+// rejects the whole file.
+func (service *WatchlistServiceImpl) ImportWatchlist(ctx context.Context, userId string, stocks []string) (*response.ImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.ImportWatchlist")
+	defer span.End()
+
+	rows, err := newBulkRows(stocks, normalizeStock)
+	if err != nil {
+		return nil, err
+	}
+
+	failBulkRows(rows, service.validateStocks(ctx, pendingBulkCodes(rows)))
+
+	inserted, err := service.Repository.AddWatchlistBatch(ctx, userId, pendingBulkCodes(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	return finishImport(rows, inserted, domainerr.ErrWatchlistDuplicate), nil
+}
+
+// AddToWatchlistBatch validates the stocks concurrently and inserts the valid ones with a single statement
+func (service *WatchlistServiceImpl) AddToWatchlistBatch(ctx context.Context, userId string, stocks []string) (*response.BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.AddToWatchlistBatch")
+	defer span.End()
+
+	rows, err := newBulkRows(stocks, normalizeStock)
+	if err != nil {
+		return nil, err
+	}
+
+	failBulkRows(rows, service.validateStocks(ctx, pendingBulkCodes(rows)))
+
+	added, err := service.Repository.AddWatchlistBatch(ctx, userId, pendingBulkCodes(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	return finishBatch(rows, added, response.BatchItemAdded, domainerr.ErrWatchlistDuplicate), nil
+}
+
+func (service *WatchlistServiceImpl) RemoveFromWatchlistBatch(ctx context.Context, userId string, stocks []string) (*response.BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.RemoveFromWatchlistBatch")
+	defer span.End()
+
+	rows, err := newBulkRows(stocks, normalizeStock)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := service.Repository.RemoveWatchlistBatch(ctx, userId, pendingBulkCodes(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	return finishBatch(rows, removed, response.BatchItemRemoved, domainerr.ErrWatchlistNotFound), nil
+}
+
+// validateStocks looks the stocks up with a fixed pool of workers and returns
+// an error message for every stock that could not be confirmed
+func (service *WatchlistServiceImpl) validateStocks(ctx context.Context, stocks []string) map[string]string {
+	messages := make(map[string]string)
+	jobs := make(chan string)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range min(stockValidationWorkers, len(stocks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stock := range jobs {
+				if _, err := service.stockClient.GetStock(ctx, stock); err != nil {
+					mu.Lock()
+					messages[stock] = quoteErrorMessage(err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, stock := range stocks {
+		jobs <- stock
+	}
+	close(jobs)
+	wg.Wait()
+
+	return messages
+}
+
+func (service *WatchlistServiceImpl) ExportWatchlist(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistService.ExportWatchlist")
+	defer span.End()
+
+	watchlist, err := service.Repository.GetWatchlistByUserID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if watchlist == nil {
+		watchlist = []string{}
+	}
+	return watchlist, nil
+}
+
+// quoteErrorMessage converts a per-stock quote error into a message that is safe to return to the client
+func quoteErrorMessage(err error) string {
+	var serviceErr *domainerr.ServiceError
+	switch {
+	case errors.As(err, &serviceErr):
+		return serviceErr.Message
+
+	case errors.Is(err, domainerr.ErrStockServiceUnavailable):
+		return domainerr.ErrStockServiceUnavailable.Error()
+
+	case errors.Is(err, domainerr.ErrUnsupportedExchange):
+		return domainerr.ErrUnsupportedExchange.Error()
+
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, domainerr.ErrServiceTimeout):
+		return domainerr.ErrServiceTimeout.Error()
+
+	default:
+		return domainerr.ErrQuoteUnavailable.Error()
+	}
+}
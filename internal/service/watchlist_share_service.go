@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const publicWatchlistPath = "/api/v1/public/watchlists/"
+
+// 24 random bytes give a 32 character slug that cannot be guessed or enumerated
+const shareSlugBytes = 24
+
+type WatchlistShareService interface {
+	CreateShare(ctx context.Context, userId string, request request.CreateWatchlistShareRequest) (*response.CreateWatchlistShareResponse, error)
+	GetShares(ctx context.Context, userId string) (*response.GetWatchlistSharesResponse, error)
+	RotateShare(ctx context.Context, userId string, shareId string) (*response.RotateWatchlistShareResponse, error)
+	RevokeShare(ctx context.Context, userId string, shareId string) (*response.RevokeWatchlistShareResponse, error)
+	GetPublicWatchlist(ctx context.Context, slug string) (*response.GetPublicWatchlistResponse, error)
+}
+
+type WatchlistShareServiceImpl struct {
+	Repository          repository.WatchlistShareRepository
+	WatchlistRepository repository.WatchlistRepository
+}
+
+func NewWatchlistShareService(repository repository.WatchlistShareRepository, watchlistRepository repository.WatchlistRepository) WatchlistShareService {
+	return &WatchlistShareServiceImpl{
+		Repository:          repository,
+		WatchlistRepository: watchlistRepository,
+	}
+}
+
+func (service *WatchlistShareServiceImpl) CreateShare(ctx context.Context, userId string, request request.CreateWatchlistShareRequest) (*response.CreateWatchlistShareResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareService.CreateShare")
+	defer span.End()
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, domainerr.ErrWatchlistShareExpiryInvalid
+	}
+
+	slug, err := newShareSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	share := &entity.WatchlistShare{
+		ID:        uuid.New(),
+		UserID:    userId,
+		Slug:      slug,
+		Title:     strings.TrimSpace(request.Title),
+		Tag:       strings.ToLower(strings.TrimSpace(request.Tag)),
+		ExpiresAt: request.ExpiresAt,
+	}
+
+	if err := service.Repository.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+
+	response := &response.CreateWatchlistShareResponse{
+		Message: "Watchlist shared successfully",
+		Data:    newShareResponse(*share),
+	}
+	return response, nil
+}
+
+func (service *WatchlistShareServiceImpl) GetShares(ctx context.Context, userId string) (*response.GetWatchlistSharesResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareService.GetShares")
+	defer span.End()
+
+	shares, err := service.Repository.GetShares(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.WatchlistShare, 0, len(shares))
+	for _, share := range shares {
+		data = append(data, newShareResponse(share))
+	}
+
+	response := &response.GetWatchlistSharesResponse{
+		Message: "Watchlist shares retrieved successfully",
+		Data:    data,
+	}
+	return response, nil
+}
+
+func (service *WatchlistShareServiceImpl) RotateShare(ctx context.Context, userId string, shareId string) (*response.RotateWatchlistShareResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareService.RotateShare")
+	defer span.End()
+
+	slug, err := newShareSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := service.Repository.RotateShare(ctx, userId, shareId, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &response.RotateWatchlistShareResponse{
+		Message: "Share link rotated successfully",
+		Data:    newShareResponse(*share),
+	}
+	return response, nil
+}
+
+func (service *WatchlistShareServiceImpl) RevokeShare(ctx context.Context, userId string, shareId string) (*response.RevokeWatchlistShareResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareService.RevokeShare")
+	defer span.End()
+
+	if err := service.Repository.RevokeShare(ctx, userId, shareId); err != nil {
+		return nil, err
+	}
+
+	response := &response.RevokeWatchlistShareResponse{
+		Message: "Share link revoked successfully",
+	}
+	return response, nil
+}
+
+func (service *WatchlistShareServiceImpl) GetPublicWatchlist(ctx context.Context, slug string) (*response.GetPublicWatchlistResponse, error) {
+	ctx, span := tracing.Start(ctx, "WatchlistShareService.GetPublicWatchlist")
+	defer span.End()
+
+	share, err := service.Repository.ViewShare(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := service.WatchlistRepository.GetWatchlistEntries(ctx, share.UserID, entity.WatchlistFilter{
+		Tag:  share.Tag,
+		Sort: entity.WatchlistSortStock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := make([]entity.PublicWatchlistEntry, 0, len(entries))
+	for _, entry := range entries {
+		stocks = append(stocks, entity.PublicWatchlistEntry{
+			Stock:      entry.Stock,
+			AddedAt:    entry.AddedAt,
+			Note:       entry.Note,
+			TargetBuy:  entry.TargetBuy,
+			TargetSell: entry.TargetSell,
+			Tags:       entry.Tags,
+		})
+	}
+
+	response := &response.GetPublicWatchlistResponse{
+		Message: "Watchlist retrieved successfully",
+		Data: response.PublicWatchlist{
+			Title:     share.Title,
+			Tag:       share.Tag,
+			SharedAt:  share.CreatedAt,
+			ExpiresAt: share.ExpiresAt,
+			Stocks:    stocks,
+		},
+	}
+	return response, nil
+}
+
+func newShareResponse(share entity.WatchlistShare) response.WatchlistShare {
+	return response.WatchlistShare{
+		WatchlistShare: share,
+		Path:           publicWatchlistPath + share.Slug,
+	}
+}
+
+func newShareSlug() (string, error) {
+	random := make([]byte, shareSlugBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+const serviceName = "stock-backend"
+
+// The global provider is resolved when a span starts, so spans started before
+// Setup are simply not recorded
+var tracer = otel.Tracer("stock_backend")
+
+// Start opens a span as a child of the span carried by ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, opts...)
+}
+
+// Setup installs the W3C trace context propagator and the exporter named by
+// exporter: otlp (configured with the standard OTEL_EXPORTER_OTLP_* variables),
+// stdout or none. The returned function flushes the spans that are still buffered.
+func Setup(ctx context.Context, exporterName string) (func(ctx context.Context) error, error) {
+	// The trace context of the gateway is passed on to the Stock Backend even when nothing is exported
+	setPropagator()
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterNone, "":
+		return func(ctx context.Context) error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("unknown TRACING_EXPORTER %q", exporterName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider(ctx, sdktrace.WithBatcher(exporter))
+	if err != nil {
+		return nil, err
+	}
+	return provider.Shutdown, nil
+}
+
+// NewProvider installs a tracer provider for this service as the global one.
+// Tests pass sdktrace.WithSyncer with a tracetest.InMemoryExporter to assert on
+// the spans. Sampling follows OTEL_TRACES_SAMPLER, which defaults to always
+// sampling unless the caller's traceparent says otherwise.
+func NewProvider(ctx context.Context, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)...)
+	otel.SetTracerProvider(provider)
+	setPropagator()
+	return provider, nil
+}
+
+func setPropagator() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"stock_backend/internal/client"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// A triggered alert is re-armed only after the price moves back past the
+// threshold by this fraction, so a price hovering around it doesn't re-fire.
+const alertHysteresis = 0.01
+
+type alertDecision int
+
+const (
+	alertUnchanged alertDecision = iota
+	alertTrigger
+	alertRearm
+)
+
+type AlertWorker struct {
+	repository  repository.AlertRepository
+	stockClient client.StockClient
+	notifier    notifier.Notifier
+	lock        *RedisLock
+	interval    time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewAlertWorker(repository repository.AlertRepository, stockClient client.StockClient, notifier notifier.Notifier, redisDb *redis.Client, interval time.Duration) *AlertWorker {
+	return &AlertWorker{
+		repository:  repository,
+		stockClient: stockClient,
+		notifier:    notifier,
+		lock:        NewRedisLock(redisDb, "lock:alert-worker", interval+5*time.Second),
+		interval:    interval,
+	}
+}
+
+// Start runs the evaluation loop in the background until ctx is cancelled or Stop is called
+func (worker *AlertWorker) Start(ctx context.Context) {
+	ctx, worker.cancel = context.WithCancel(ctx)
+	worker.done = make(chan struct{})
+
+	go worker.run(ctx)
+}
+
+// Stop cancels the loop and waits for the evaluation in progress to finish
+func (worker *AlertWorker) Stop(ctx context.Context) error {
+	if worker.cancel == nil {
+		return nil
+	}
+	worker.cancel()
+
+	select {
+	case <-worker.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (worker *AlertWorker) run(ctx context.Context) {
+	defer close(worker.done)
+
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			worker.tick(ctx)
+		}
+	}
+}
+
+func (worker *AlertWorker) tick(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "AlertWorker.tick")
+	defer span.End()
+
+	acquired, err := worker.lock.Acquire(ctx)
+	if err != nil {
+		logging.Error(ctx, "acquire alert lock failed", "error", err)
+		return
+	}
+
+	// Another replica is evaluating
+	if !acquired {
+		return
+	}
+
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+		defer cancel()
+
+		if err := worker.lock.Release(releaseCtx); err != nil {
+			logging.Error(releaseCtx, "release alert lock failed", "error", err)
+		}
+	}()
+
+	evalCtx, cancel := context.WithTimeout(ctx, worker.interval)
+	defer cancel()
+
+	if err := worker.evaluate(evalCtx); err != nil {
+		logging.Error(ctx, "evaluate alerts failed", "error", err)
+	}
+}
+
+func (worker *AlertWorker) evaluate(ctx context.Context) error {
+	alerts, err := worker.repository.GetActiveAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	stocks := []string{}
+	seen := map[string]bool{}
+	for _, alert := range alerts {
+		if !seen[alert.Stock] {
+			seen[alert.Stock] = true
+			stocks = append(stocks, alert.Stock)
+		}
+	}
+
+	quotes := map[string]*entity.Quote{}
+	for _, result := range worker.stockClient.GetQuotes(ctx, stocks) {
+		if result.Err != nil {
+			logging.Error(ctx, "get quote failed", "stock", result.Stock, "error", result.Err)
+			continue
+		}
+		quotes[result.Stock] = result.Quote
+	}
+
+	rearmIds := []string{}
+	for _, alert := range alerts {
+		quote, ok := quotes[alert.Stock]
+		if !ok {
+			continue
+		}
+
+		switch evaluateAlert(alert, quote) {
+		case alertTrigger:
+			event := &entity.AlertEvent{
+				AlertID:       alert.ID,
+				Price:         quote.LastPrice,
+				ChangePercent: quote.ChangePercent,
+			}
+			// The deliveries are queued in the transaction that disarms the alert. When
+			// they cannot be resolved the alert stays armed and is evaluated again next tick.
+			deliveries, err := worker.notifier.Deliveries(ctx, alert.UserID, alertMessage(alert, event))
+			if err != nil {
+				logging.Error(ctx, "resolve alert notification failed", "alert_id", alert.ID, "error", err)
+				continue
+			}
+
+			if _, err := worker.repository.TriggerAlert(ctx, event, deliveries); err != nil {
+				logging.Error(ctx, "trigger alert failed", "alert_id", alert.ID, "error", err)
+			}
+
+		case alertRearm:
+			rearmIds = append(rearmIds, alert.ID.String())
+		}
+	}
+
+	return worker.repository.RearmAlerts(ctx, rearmIds)
+}
+
+func evaluateAlert(alert entity.Alert, quote *entity.Quote) alertDecision {
+	var crossed, cleared bool
+
+	switch alert.Condition {
+	case entity.AlertPriceAbove:
+		crossed = quote.LastPrice >= alert.Threshold
+		cleared = quote.LastPrice < alert.Threshold*(1-alertHysteresis)
+
+	case entity.AlertPriceBelow:
+		crossed = quote.LastPrice <= alert.Threshold
+		cleared = quote.LastPrice > alert.Threshold*(1+alertHysteresis)
+
+	case entity.AlertPercentChange:
+		change := math.Abs(quote.ChangePercent)
+		crossed = change >= alert.Threshold
+		cleared = change < alert.Threshold*(1-alertHysteresis)
+	}
+
+	switch {
+	case alert.Armed && crossed:
+		return alertTrigger
+	case !alert.Armed && cleared:
+		return alertRearm
+	default:
+		return alertUnchanged
+	}
+}
+
+func alertMessage(alert entity.Alert, event *entity.AlertEvent) notifier.Message {
+	var body string
+	switch alert.Condition {
+	case entity.AlertPriceAbove:
+		body = fmt.Sprintf("%s is at %.2f, above your alert at %.2f", alert.Stock, event.Price, alert.Threshold)
+	case entity.AlertPriceBelow:
+		body = fmt.Sprintf("%s is at %.2f, below your alert at %.2f", alert.Stock, event.Price, alert.Threshold)
+	default:
+		body = fmt.Sprintf("%s moved %.2f%% today, past your alert at %.2f%%", alert.Stock, event.ChangePercent, alert.Threshold)
+	}
+
+	return notifier.Message{
+		Kind:  "alert_triggered",
+		Title: fmt.Sprintf("%s price alert", alert.Stock),
+		Body:  body,
+		Data: map[string]any{
+			"alert_id":       alert.ID.String(),
+			"stock":          alert.Stock,
+			"price":          event.Price,
+			"change_percent": event.ChangePercent,
+		},
+	}
+}
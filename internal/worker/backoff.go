@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"errors"
+	"time"
+)
+
+var errChannelNotConfigured = errors.New("channel is not configured")
+
+// backoff returns base * 2^(attempt-1), capped at max
+func backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
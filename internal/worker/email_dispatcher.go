@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"context"
+	"net/mail"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/mailer"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"time"
+)
+
+const (
+	emailBatchSize   = 20
+	emailBaseBackoff = time.Minute
+	emailMaxBackoff  = 2 * time.Hour
+	emailSendTimeout = 30 * time.Second
+)
+
+// EmailDispatcher sends the emails queued in the outbox. Each email is retried
+// with exponential backoff until it reaches its max attempts and is marked failed.
+type EmailDispatcher struct {
+	repository repository.EmailOutboxRepository
+	sender     mailer.EmailSender
+	interval   time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewEmailDispatcher(repository repository.EmailOutboxRepository, sender mailer.EmailSender, interval time.Duration) *EmailDispatcher {
+	return &EmailDispatcher{
+		repository: repository,
+		sender:     sender,
+		interval:   interval,
+	}
+}
+
+func (dispatcher *EmailDispatcher) Start(ctx context.Context) {
+	ctx, dispatcher.cancel = context.WithCancel(ctx)
+	dispatcher.done = make(chan struct{})
+
+	go dispatcher.run(ctx)
+}
+
+func (dispatcher *EmailDispatcher) Stop(ctx context.Context) error {
+	if dispatcher.cancel == nil {
+		return nil
+	}
+	dispatcher.cancel()
+
+	select {
+	case <-dispatcher.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (dispatcher *EmailDispatcher) run(ctx context.Context) {
+	defer close(dispatcher.done)
+
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := dispatcher.DispatchOnce(ctx); err != nil {
+				logging.Error(ctx, "dispatch emails failed", "error", err)
+			}
+		}
+	}
+}
+
+// DispatchOnce sends one batch of due emails
+func (dispatcher *EmailDispatcher) DispatchOnce(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "EmailDispatcher.DispatchOnce")
+	defer span.End()
+
+	emails, err := dispatcher.repository.ClaimDueEmails(ctx, emailBatchSize, emailBatchSize*emailSendTimeout)
+	if err != nil {
+		return err
+	}
+
+	for i, email := range emails {
+		if ctx.Err() != nil {
+			dispatcher.release(ctx, emails[i:])
+			return ctx.Err()
+		}
+		dispatcher.send(ctx, email)
+	}
+	return nil
+}
+
+// release hands back the claimed emails that were not attempted before the dispatcher stopped
+func (dispatcher *EmailDispatcher) release(ctx context.Context, emails []entity.EmailOutbox) {
+	emailIds := make([]int64, 0, len(emails))
+	for _, email := range emails {
+		emailIds = append(emailIds, email.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+
+	if err := dispatcher.repository.ReleaseEmails(ctx, emailIds); err != nil {
+		logging.Error(ctx, "release emails failed", "error", err)
+	}
+}
+
+// send delivers one email and records the outcome. A send that has started is
+// finished even when the dispatcher is stopped, so it is not cut off half way.
+func (dispatcher *EmailDispatcher) send(ctx context.Context, email entity.EmailOutbox) {
+	ctx = context.WithoutCancel(ctx)
+	sendCtx, cancel := context.WithTimeout(ctx, emailSendTimeout)
+	start := time.Now()
+	err := dispatcher.sender.Send(sendCtx, &mailer.Message{
+		To:      mail.Address{Address: email.Recipient},
+		Subject: email.Subject,
+		Text:    email.TextBody,
+		HTML:    email.HTMLBody,
+	})
+	cancel()
+	emailSendDuration.Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		emailSends.WithLabelValues("sent").Inc()
+		if err := dispatcher.repository.MarkEmailSent(ctx, email.ID); err != nil {
+			logging.Error(ctx, "mark email sent failed", "email_id", email.ID, "error", err)
+		}
+		return
+	}
+
+	email.Attempts++
+	email.LastError = err.Error()
+	email.NextAttemptAt = time.Now().Add(backoff(email.Attempts, emailBaseBackoff, emailMaxBackoff))
+	email.Status = entity.EmailPending
+	outcome := "retry"
+	if email.Attempts >= email.MaxAttempts {
+		email.Status = entity.EmailFailed
+		outcome = "failed"
+		logging.Error(ctx, "email failed after the last attempt", "email_id", email.ID, "attempts", email.Attempts, "error", err)
+	}
+	emailSends.WithLabelValues(outcome).Inc()
+
+	if err := dispatcher.repository.MarkEmailFailed(ctx, email); err != nil {
+		logging.Error(ctx, "record email failure failed", "email_id", email.ID, "error", err)
+	}
+}
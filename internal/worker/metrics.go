@@ -0,0 +1,20 @@
+package worker
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	emailSends = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "email_sends_total",
+		Help: "Outbox emails handed to the transport by outcome: sent, retry when another attempt is scheduled, or failed after the last attempt.",
+	}, []string{"outcome"})
+
+	emailSendDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "email_send_duration_seconds",
+		Help:    "Time the email transport took to accept or reject a message.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	})
+)
+
+func init() {
+	prometheus.MustRegister(emailSends, emailSendDuration)
+}
@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/logging"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/tracing"
+	"time"
+)
+
+const (
+	notificationBatchSize   = 50
+	notificationMaxAttempts = 6
+	notificationBaseBackoff = 30 * time.Second
+	notificationMaxBackoff  = time.Hour
+)
+
+// NotificationWorker sends queued deliveries through their channel. Failed
+// deliveries are retried with exponential backoff until they are marked dead.
+type NotificationWorker struct {
+	repository repository.NotificationDeliveryRepository
+	channels   map[string]notifier.Channel
+	interval   time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewNotificationWorker(repository repository.NotificationDeliveryRepository, interval time.Duration, channels ...notifier.Channel) *NotificationWorker {
+	registered := map[string]notifier.Channel{}
+	for _, channel := range channels {
+		registered[channel.Name()] = channel
+	}
+
+	return &NotificationWorker{
+		repository: repository,
+		channels:   registered,
+		interval:   interval,
+	}
+}
+
+func (worker *NotificationWorker) Start(ctx context.Context) {
+	ctx, worker.cancel = context.WithCancel(ctx)
+	worker.done = make(chan struct{})
+
+	go worker.run(ctx)
+}
+
+func (worker *NotificationWorker) Stop(ctx context.Context) error {
+	if worker.cancel == nil {
+		return nil
+	}
+	worker.cancel()
+
+	select {
+	case <-worker.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (worker *NotificationWorker) run(ctx context.Context) {
+	defer close(worker.done)
+
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := worker.DispatchOnce(ctx); err != nil {
+				logging.Error(ctx, "dispatch notifications failed", "error", err)
+			}
+		}
+	}
+}
+
+// DispatchOnce sends one batch of due deliveries
+func (worker *NotificationWorker) DispatchOnce(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "NotificationWorker.DispatchOnce")
+	defer span.End()
+
+	deliveries, err := worker.repository.ClaimDueDeliveries(ctx, notificationBatchSize, 2*worker.interval+time.Minute)
+	if err != nil {
+		return err
+	}
+
+	for i, delivery := range deliveries {
+		if ctx.Err() != nil {
+			worker.release(ctx, deliveries[i:])
+			return ctx.Err()
+		}
+		worker.deliver(ctx, delivery)
+	}
+	return nil
+}
+
+// release hands back the leased deliveries that were not attempted before the worker stopped
+func (worker *NotificationWorker) release(ctx context.Context, deliveries []entity.NotificationDelivery) {
+	deliveryIds := make([]int64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryIds = append(deliveryIds, delivery.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+
+	if err := worker.repository.ReleaseDeliveries(ctx, deliveryIds); err != nil {
+		logging.Error(ctx, "release deliveries failed", "error", err)
+	}
+}
+
+// deliver sends one delivery and records the outcome. A send that has started is
+// finished even when the worker is stopped, so its result is not lost.
+func (worker *NotificationWorker) deliver(ctx context.Context, delivery entity.NotificationDelivery) {
+	ctx = context.WithoutCancel(ctx)
+	channel, ok := worker.channels[delivery.Channel]
+
+	var err error
+	if !ok {
+		err = errChannelNotConfigured
+	} else {
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = channel.Send(sendCtx, delivery.Target, notifier.Message{
+			Kind:  delivery.Kind,
+			Title: delivery.Title,
+			Body:  delivery.Body,
+			Data:  delivery.Data,
+		})
+		cancel()
+	}
+
+	if err == nil {
+		if err := worker.repository.MarkDeliverySent(ctx, delivery.ID); err != nil {
+			logging.Error(ctx, "mark delivery sent failed", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts, notificationBaseBackoff, notificationMaxBackoff))
+	delivery.Status = entity.DeliveryPending
+	if delivery.Attempts >= notificationMaxAttempts {
+		delivery.Status = entity.DeliveryDead
+		logging.Error(ctx, "notification delivery is dead", "delivery_id", delivery.ID, "channel", delivery.Channel, "error", err)
+	}
+
+	if err := worker.repository.MarkDeliveryFailed(ctx, delivery); err != nil {
+		logging.Error(ctx, "record delivery failure failed", "delivery_id", delivery.ID, "error", err)
+	}
+}
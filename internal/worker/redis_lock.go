@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Only delete the key when it still holds our token, so an expired lock taken over by another replica is left alone
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLock is a best-effort distributed lock that lets a single replica run a job at a time
+type RedisLock struct {
+	client *redis.Client
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+func NewRedisLock(client *redis.Client, key string, ttl time.Duration) *RedisLock {
+	return &RedisLock{
+		client: client,
+		key:    key,
+		token:  uuid.NewString(),
+		ttl:    ttl,
+	}
+}
+
+func (lock *RedisLock) Acquire(ctx context.Context) (bool, error) {
+	return lock.client.SetNX(ctx, lock.key, lock.token, lock.ttl).Result()
+}
+
+func (lock *RedisLock) Release(ctx context.Context) error {
+	return releaseLockScript.Run(ctx, lock.client, []string{lock.key}, lock.token).Err()
+}
@@ -0,0 +1,22 @@
+CREATE TABLE alerts (
+    id UUID PRIMARY KEY NOT NULL,
+    userid UUID NOT NULL,
+    stock CHAR(4) NOT NULL,
+    condition VARCHAR(20) NOT NULL,
+    threshold NUMERIC(18, 4) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    armed BOOLEAN NOT NULL DEFAULT TRUE,
+    last_triggered_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_alerts_condition
+        CHECK (condition IN ('price_above', 'price_below', 'percent_change')),
+    CONSTRAINT fk_alerts_watchlist
+        FOREIGN KEY (userid, stock)
+        REFERENCES watchlist(userid, stock)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_alerts_userid ON alerts (userid);
+CREATE INDEX idx_alerts_active ON alerts (stock) WHERE active;
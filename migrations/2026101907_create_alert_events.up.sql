@@ -0,0 +1,13 @@
+CREATE TABLE alert_events (
+    id BIGSERIAL PRIMARY KEY,
+    alertid UUID NOT NULL,
+    price NUMERIC(18, 4) NOT NULL,
+    change_percent NUMERIC(9, 4) NOT NULL,
+    triggered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_alert_events_alerts
+        FOREIGN KEY (alertid)
+        REFERENCES alerts(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_alert_events_alertid ON alert_events (alertid, triggered_at DESC);
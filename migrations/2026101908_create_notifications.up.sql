@@ -0,0 +1,17 @@
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY NOT NULL,
+    userid UUID NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_notifications_users
+        FOREIGN KEY (userid)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_notifications_userid ON notifications (userid, created_at DESC);
@@ -0,0 +1,15 @@
+CREATE TABLE notification_preferences (
+    userid UUID NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    target TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT notification_preferences_pkey PRIMARY KEY (userid, channel),
+    CONSTRAINT chk_notification_preferences_channel
+        CHECK (channel IN ('email', 'webhook', 'apns', 'in_app')),
+    CONSTRAINT fk_notification_preferences_users
+        FOREIGN KEY (userid)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
@@ -0,0 +1,26 @@
+CREATE TABLE notification_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    userid UUID NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ DEFAULT NULL,
+    CONSTRAINT chk_notification_deliveries_status
+        CHECK (status IN ('pending', 'sent', 'dead')),
+    CONSTRAINT fk_notification_deliveries_users
+        FOREIGN KEY (userid)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_notification_deliveries_due ON notification_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,19 @@
+CREATE TABLE email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    recipient VARCHAR(254) NOT NULL,
+    subject VARCHAR(200) NOT NULL,
+    html_body TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ DEFAULT NULL,
+    CONSTRAINT chk_email_outbox_status
+        CHECK (status IN ('pending', 'sent', 'failed'))
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_email_outbox_status ON email_outbox (status, created_at DESC);
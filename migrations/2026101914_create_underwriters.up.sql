@@ -0,0 +1,31 @@
+CREATE TABLE underwriters(
+    code CHAR(2) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO underwriters (code, name) VALUES
+    ('AK', 'UBS Sekuritas Indonesia'),
+    ('AZ', 'Sucor Sekuritas'),
+    ('BK', 'J.P. Morgan Sekuritas Indonesia'),
+    ('CC', 'Mandiri Sekuritas'),
+    ('DR', 'RHB Sekuritas Indonesia'),
+    ('DX', 'Bahana Sekuritas'),
+    ('EP', 'MNC Sekuritas'),
+    ('GR', 'Panin Sekuritas'),
+    ('IF', 'Samuel Sekuritas Indonesia'),
+    ('KI', 'Ciptadana Sekuritas Asia'),
+    ('KZ', 'CLSA Sekuritas Indonesia'),
+    ('LG', 'Trimegah Sekuritas Indonesia'),
+    ('NI', 'BNI Sekuritas'),
+    ('OD', 'BRI Danareksa Sekuritas'),
+    ('PD', 'Indo Premier Sekuritas'),
+    ('RX', 'Macquarie Sekuritas Indonesia'),
+    ('SQ', 'BCA Sekuritas'),
+    ('XC', 'Ajaib Sekuritas Asia'),
+    ('XL', 'Stockbit Sekuritas Digital'),
+    ('YP', 'Mirae Asset Sekuritas Indonesia'),
+    ('YU', 'CGS International Sekuritas Indonesia'),
+    ('ZP', 'Maybank Sekuritas Indonesia')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,7 @@
+-- NOT VALID keeps existing rows with unknown codes while every new favorite is checked
+ALTER TABLE favorites
+    ADD CONSTRAINT fk_favorite_underwriters
+        FOREIGN KEY (underwriterId)
+        REFERENCES underwriters(code)
+        ON UPDATE CASCADE
+        NOT VALID;
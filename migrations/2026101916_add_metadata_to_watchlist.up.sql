@@ -0,0 +1,12 @@
+ALTER TABLE watchlist
+    ADD COLUMN added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN note VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN target_buy NUMERIC(18, 4) CHECK (target_buy > 0),
+    ADD COLUMN target_sell NUMERIC(18, 4) CHECK (target_sell > 0),
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_watchlist_tags ON watchlist USING GIN (tags);
+
+ALTER TABLE watchlist
+    ADD CONSTRAINT watchlist_target_range
+        CHECK (target_buy IS NULL OR target_sell IS NULL OR target_buy < target_sell);
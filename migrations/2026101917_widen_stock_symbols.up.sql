@@ -0,0 +1,15 @@
+-- Stocks are stored as EXCHANGE:CODE. Existing rows are IDX codes.
+ALTER TABLE alerts DROP CONSTRAINT fk_alerts_watchlist;
+
+ALTER TABLE watchlist
+    ALTER COLUMN stock TYPE VARCHAR(32) USING 'IDX:' || TRIM(stock);
+
+ALTER TABLE alerts
+    ALTER COLUMN stock TYPE VARCHAR(32) USING 'IDX:' || TRIM(stock);
+
+ALTER TABLE alerts
+    ADD CONSTRAINT fk_alerts_watchlist
+        FOREIGN KEY (userid, stock)
+        REFERENCES watchlist(userid, stock)
+        ON DELETE CASCADE
+        ON UPDATE CASCADE;
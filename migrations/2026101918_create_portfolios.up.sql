@@ -0,0 +1,46 @@
+CREATE TABLE portfolios (
+    id UUID PRIMARY KEY NOT NULL,
+    userid UUID NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    cost_method VARCHAR(10) NOT NULL DEFAULT 'average',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_portfolios_name UNIQUE (userid, name),
+    CONSTRAINT chk_portfolios_cost_method
+        CHECK (cost_method IN ('average', 'fifo')),
+    CONSTRAINT fk_portfolios_users
+        FOREIGN KEY (userid)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- The ledger is the source of truth, holdings are derived from it on read
+CREATE TABLE portfolio_transactions (
+    id UUID PRIMARY KEY NOT NULL,
+    portfolioid UUID NOT NULL,
+    stock VARCHAR(32) NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    quantity NUMERIC(24, 8) NOT NULL DEFAULT 0,
+    price NUMERIC(24, 8) NOT NULL DEFAULT 0,
+    fee NUMERIC(24, 8) NOT NULL DEFAULT 0,
+    amount NUMERIC(24, 8) NOT NULL DEFAULT 0,
+    ratio NUMERIC(24, 8) NOT NULL DEFAULT 0,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    traded_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_portfolio_transactions_type
+        CHECK (type IN ('buy', 'sell', 'dividend', 'split')),
+    CONSTRAINT chk_portfolio_transactions_values
+        CHECK (
+            (type IN ('buy', 'sell') AND quantity > 0 AND price > 0 AND fee >= 0) OR
+            (type = 'dividend' AND amount > 0) OR
+            (type = 'split' AND ratio > 0)
+        ),
+    CONSTRAINT fk_portfolio_transactions_portfolios
+        FOREIGN KEY (portfolioid)
+        REFERENCES portfolios(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_portfolios_userid ON portfolios (userid);
+CREATE INDEX idx_portfolio_transactions_ledger ON portfolio_transactions (portfolioid, stock, traded_at, created_at);
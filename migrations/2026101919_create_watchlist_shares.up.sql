@@ -0,0 +1,20 @@
+CREATE TABLE watchlist_shares (
+    id UUID PRIMARY KEY NOT NULL,
+    userid UUID NOT NULL,
+    slug VARCHAR(64) NOT NULL,
+    title VARCHAR(100) NOT NULL DEFAULT '',
+    tag VARCHAR(32) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ DEFAULT NULL,
+    revoked_at TIMESTAMPTZ DEFAULT NULL,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_watchlist_shares_slug UNIQUE (slug),
+    CONSTRAINT fk_watchlist_shares_users
+        FOREIGN KEY (userid)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_watchlist_shares_userid ON watchlist_shares (userid);
@@ -0,0 +1,386 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"stock_backend/internal/circuit"
+	"stock_backend/internal/client"
+	"stock_backend/internal/client/fakestock"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/worker"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alertPath  = "/api/v1/alerts"
+	alertStock = "IDX:BBCA"
+)
+
+var alertId string
+
+func TestCreateAlertStockNotInWatchlist(t *testing.T) {
+	requestBody := request.CreateAlertRequest{
+		Stock:     alertStock,
+		Condition: "price_above",
+		Threshold: 10000,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, alertPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.Equal(t, domainerr.ErrAlertStockNotInWatchlist.Error(), result.Message)
+}
+
+func TestCreateAlert(t *testing.T) {
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO watchlist (userid, stock) VALUES ($1, $2)", userId, alertStock)
+	require.Nil(t, err)
+
+	requestBody := request.CreateAlertRequest{
+		Stock:     alertStock,
+		Condition: "price_above",
+		Threshold: 10000,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.CreateAlertResponse](requestBody, alertPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "Alert created successfully", result.Message)
+	assert.Equal(t, alertStock, result.Data.Stock)
+	assert.True(t, result.Data.Armed)
+	alertId = result.Data.ID.String()
+}
+
+func TestCreateAlertInvalidCondition(t *testing.T) {
+	requestBody := request.CreateAlertRequest{
+		Stock:     alertStock,
+		Condition: "volume_spike",
+		Threshold: 10000,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, alertPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "Condition must be one of price_above price_below percent_change", result.Message)
+}
+
+func TestGetAlerts(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetAlertsResponse](nil, alertPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, alertId, result.Data[0].ID.String())
+}
+
+func TestUpdateAlert(t *testing.T) {
+	threshold := 12000.0
+	requestBody := request.UpdateAlertRequest{
+		Threshold: &threshold,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", alertPath, alertId)
+	result, statusCode, err := PerformRequest[*response.UpdateAlertResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, threshold, result.Data.Threshold)
+	assert.True(t, result.Data.Active)
+}
+
+func TestGetAlertEvents(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/events", alertPath, alertId)
+	result, statusCode, err := PerformRequest[*response.GetAlertEventsResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, result.Data)
+}
+
+// recordingNotifier keeps the notifications and resolves them to an in-app delivery
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []notifier.Message
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, userId string, message notifier.Message) error {
+	_, err := n.Deliveries(ctx, userId, message)
+	return err
+}
+
+func (n *recordingNotifier) Deliveries(ctx context.Context, userId string, message notifier.Message) ([]entity.NotificationDelivery, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, message)
+	return []entity.NotificationDelivery{{
+		UserID:  userId,
+		Channel: entity.ChannelInApp,
+		Target:  userId,
+		Kind:    message.Kind,
+		Title:   message.Title,
+		Body:    message.Body,
+		Data:    message.Data,
+	}}, nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.messages)
+}
+
+func TestAlertWorkerTriggersAlert(t *testing.T) {
+	stockServer.SetQuotes(fakestock.Quote{Code: "BBCA", LastPrice: 12500, Change: 3000, ChangePercent: 31.58, Volume: 90000000})
+	t.Cleanup(func() {
+		stockServer.SetQuotes(fakestock.Quote{Code: "BBCA", LastPrice: 9500, Change: 125, ChangePercent: 1.33, Volume: 52000000})
+	})
+
+	recorder := &recordingNotifier{}
+	stockClient := client.NewStockClient(stockServer.Routes(), func(name string) client.Breaker {
+		return circuit.NewCircuitBreaker(name+"-alerts-test", appConfig.CircuitBreaker(name))
+	})
+	alertWorker := worker.NewAlertWorker(repository.NewAlertRepository(db), stockClient, recorder, redisDb, 100*time.Millisecond)
+	alertWorker.Start(context.Background())
+
+	assert.Eventually(t, func() bool { return recorder.count() > 0 }, 3*time.Second, 50*time.Millisecond)
+	require.Nil(t, alertWorker.Stop(context.Background()))
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/events", alertPath, alertId)
+	result, statusCode, err := PerformRequest[*response.GetAlertEventsResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, 12500.0, result.Data[0].Price)
+
+	// The delivery is committed together with the alert event
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	var deliveries int
+	err = db.QueryRow("SELECT COUNT(*) FROM notification_deliveries WHERE userid = $1 AND kind = 'alert_triggered'", userId).Scan(&deliveries)
+	require.Nil(t, err)
+	assert.Equal(t, 1, deliveries)
+
+	_, err = db.Exec("DELETE FROM notification_deliveries WHERE userid = $1 AND kind = 'alert_triggered'", userId)
+	require.Nil(t, err)
+}
+
+func TestDeleteAlertInvalidId(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", alertPath, "not-a-uuid")
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrAlertIdInvalid.Error(), result.Message)
+}
+
+func TestDeleteAlert(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", alertPath, alertId)
+	result, statusCode, err := PerformRequest[*response.DeleteAlertResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Alert deleted successfully", result.Message)
+}
+
+func TestDeleteAlertNotFound(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", alertPath, alertId)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrAlertNotFound.Error(), result.Message)
+
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	_, err = db.Exec("DELETE FROM watchlist WHERE userid = $1 AND stock = $2", userId, alertStock)
+	require.Nil(t, err)
+}
+
+// evaluationRepository serves one alert to the worker and records what the worker decided
+type evaluationRepository struct {
+	repository.AlertRepository
+
+	mu        sync.Mutex
+	alert     entity.Alert
+	triggered int
+	rearmed   int
+	evaluated int
+}
+
+func (r *evaluationRepository) GetActiveAlerts(ctx context.Context) ([]entity.Alert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return []entity.Alert{r.alert}, nil
+}
+
+func (r *evaluationRepository) TriggerAlert(ctx context.Context, event *entity.AlertEvent, deliveries []entity.NotificationDelivery) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.triggered++
+	return true, nil
+}
+
+func (r *evaluationRepository) RearmAlerts(ctx context.Context, alertIds []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rearmed += len(alertIds)
+	r.evaluated++
+	return nil
+}
+
+func (r *evaluationRepository) counts() (int, int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.triggered, r.rearmed, r.evaluated
+}
+
+func TestAlertWorkerHysteresis(t *testing.T) {
+	t.Cleanup(func() {
+		stockServer.SetQuotes(fakestock.Quote{Code: "BBCA", LastPrice: 9500, Change: 125, ChangePercent: 1.33, Volume: 52000000})
+	})
+
+	stockClient := client.NewStockClient(stockServer.Routes(), func(name string) client.Breaker {
+		return circuit.NewCircuitBreaker(name+"-hysteresis-test", appConfig.CircuitBreaker(name))
+	})
+
+	tests := []struct {
+		name          string
+		condition     string
+		threshold     float64
+		armed         bool
+		lastPrice     float64
+		changePercent float64
+		wantTrigger   bool
+		wantRearm     bool
+	}{
+		{name: "price above crosses threshold", condition: entity.AlertPriceAbove, threshold: 10000, armed: true, lastPrice: 10000, wantTrigger: true},
+		{name: "price above stays under threshold", condition: entity.AlertPriceAbove, threshold: 10000, armed: true, lastPrice: 9950},
+		{name: "price above hovers in band", condition: entity.AlertPriceAbove, threshold: 10000, armed: false, lastPrice: 9950},
+		{name: "price above stays past threshold", condition: entity.AlertPriceAbove, threshold: 10000, armed: false, lastPrice: 10050},
+		{name: "price above clears band", condition: entity.AlertPriceAbove, threshold: 10000, armed: false, lastPrice: 9899, wantRearm: true},
+
+		{name: "price below crosses threshold", condition: entity.AlertPriceBelow, threshold: 10000, armed: true, lastPrice: 10000, wantTrigger: true},
+		{name: "price below stays over threshold", condition: entity.AlertPriceBelow, threshold: 10000, armed: true, lastPrice: 10050},
+		{name: "price below hovers in band", condition: entity.AlertPriceBelow, threshold: 10000, armed: false, lastPrice: 10050},
+		{name: "price below stays past threshold", condition: entity.AlertPriceBelow, threshold: 10000, armed: false, lastPrice: 9950},
+		{name: "price below clears band", condition: entity.AlertPriceBelow, threshold: 10000, armed: false, lastPrice: 10101, wantRearm: true},
+
+		{name: "percent change crosses threshold", condition: entity.AlertPercentChange, threshold: 5, armed: true, changePercent: -5.2, wantTrigger: true},
+		{name: "percent change stays under threshold", condition: entity.AlertPercentChange, threshold: 5, armed: true, changePercent: 4.97},
+		{name: "percent change hovers in band", condition: entity.AlertPercentChange, threshold: 5, armed: false, changePercent: -4.97},
+		{name: "percent change stays past threshold", condition: entity.AlertPercentChange, threshold: 5, armed: false, changePercent: 5.5},
+		{name: "percent change clears band", condition: entity.AlertPercentChange, threshold: 5, armed: false, changePercent: 4.9, wantRearm: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastPrice := tt.lastPrice
+			if lastPrice == 0 {
+				lastPrice = 9500
+			}
+			stockServer.SetQuotes(fakestock.Quote{Code: "BBCA", LastPrice: lastPrice, ChangePercent: tt.changePercent, Volume: 52000000})
+
+			alertRepository := &evaluationRepository{alert: entity.Alert{
+				ID:        uuid.New(),
+				UserID:    uuid.NewString(),
+				Stock:     alertStock,
+				Condition: tt.condition,
+				Threshold: tt.threshold,
+				Active:    true,
+				Armed:     tt.armed,
+			}}
+			alertWorker := worker.NewAlertWorker(alertRepository, stockClient, &recordingNotifier{}, redisDb, 20*time.Millisecond)
+			alertWorker.Start(context.Background())
+
+			assert.Eventually(t, func() bool {
+				_, _, evaluated := alertRepository.counts()
+				return evaluated > 0
+			}, 3*time.Second, 10*time.Millisecond)
+			require.Nil(t, alertWorker.Stop(context.Background()))
+
+			triggered, rearmed, _ := alertRepository.counts()
+			assert.Equal(t, tt.wantTrigger, triggered > 0)
+			assert.Equal(t, tt.wantRearm, rearmed > 0)
+		})
+	}
+}
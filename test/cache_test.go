@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/response"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminCachePath = "/api/v1/admin/cache"
+
+func TestGetCacheStatus(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetCacheStatusResponse](nil, adminCachePath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, result.Data.Degraded)
+	assert.Equal(t, 0, result.Data.PendingInvalidations)
+}
+
+func TestGetCacheStatusForbidden(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, adminCachePath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, domainerr.ErrUnauthorizedAccess.Error(), result.Message)
+}
+
+func TestPurgeStockCache(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	err := redisCache.Do(context.Background(), func(ctx context.Context, client *redis.Client) error {
+		return client.Set(ctx, "stock:exists:IDX:ZZZZ", `{"found":true}`, time.Minute).Err()
+	})
+	require.Nil(t, err)
+
+	url := adminCachePath + "/stocks?stock=zzzz"
+	result, statusCode, err := PerformRequest[*response.PurgeStockCacheResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, int64(1), result.Data.Purged)
+}
+
+func TestPurgeStockCacheInvalidSymbol(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := adminCachePath + "/stocks?stock=LSE:VOD"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrUnsupportedExchange.Error(), result.Message)
+}
+
+func TestCacheDegradesWhenRedisIsDown(t *testing.T) {
+	// Nothing listens on port 1, so every call to Redis fails
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer func() {
+		_ = client.Close()
+	}()
+
+	unavailable := cache.New(client, 100*time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	_, found := unavailable.Get(ctx, "favorites:test")
+	assert.False(t, found)
+	assert.True(t, unavailable.Degraded())
+
+	unavailable.Invalidate(ctx, "favorites:test", "watchlist:test")
+	assert.Equal(t, 2, unavailable.PendingInvalidations())
+
+	// Recovery keeps the cache degraded while Redis is still unreachable
+	unavailable.Recover(ctx)
+	assert.True(t, unavailable.Degraded())
+	assert.Equal(t, 2, unavailable.PendingInvalidations())
+
+	err := unavailable.Do(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.Ping(ctx).Err()
+	})
+	assert.ErrorIs(t, err, cache.ErrDegraded)
+}
+
+func TestCacheIgnoresCallerCancellation(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer func() {
+		_ = client.Close()
+	}()
+
+	unavailable := cache.New(client, 100*time.Millisecond, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, found := unavailable.Get(ctx, "favorites:test")
+	assert.False(t, found)
+	unavailable.Set(ctx, "favorites:test", "value", time.Minute)
+	assert.False(t, unavailable.Degraded())
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	_, found = unavailable.Get(expired, "favorites:test")
+	assert.False(t, found)
+	assert.False(t, unavailable.Degraded())
+}
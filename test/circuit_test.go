@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"stock_backend/config"
+	"stock_backend/internal/circuit"
+	"stock_backend/internal/client"
+	"stock_backend/internal/client/fakestock"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminCircuitPath = "/api/v1/admin/circuit-breakers"
+
+func TestCircuitBreakerSettingsFromEnv(t *testing.T) {
+	t.Setenv("CIRCUIT_STOCK_SERVICE_SETTINGS_TEST_TIMEOUT", "2s")
+	t.Setenv("CIRCUIT_STOCK_SERVICE_SETTINGS_TEST_FAILURE_RATIO", "0.25")
+
+	cfg, err := config.Load()
+	require.Nil(t, err)
+
+	settings := circuit.NewCircuitBreaker("stock-service-settings-test", cfg.CircuitBreaker("stock-service-settings-test")).Settings()
+	assert.Equal(t, 2*time.Second, settings.Timeout)
+	assert.Equal(t, 0.25, settings.FailureRatio)
+	assert.Equal(t, circuit.DefaultSettings.MaxRequests, settings.MaxRequests)
+	assert.Equal(t, circuit.DefaultSettings.Interval, settings.Interval)
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	server, stockClient, breaker := newFakeStockClientWithBreaker(t, config.CircuitBreaker{
+		MinRequests: 2,
+		MaxRequests: 1,
+		Timeout:     200 * time.Millisecond,
+	})
+	for range 6 {
+		server.Script(fakestock.StockPath, fakestock.Step{Status: http.StatusServiceUnavailable})
+	}
+
+	// Each request is retried 3 times before it counts as one failure
+	for range 2 {
+		_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+		assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	}
+	assert.Equal(t, gobreaker.StateOpen, breaker.State())
+	assert.Len(t, server.Requests(fakestock.StockPath), 6)
+
+	// An open breaker answers without calling the stock service
+	_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	assert.Len(t, server.Requests(fakestock.StockPath), 6)
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, gobreaker.StateHalfOpen, breaker.State())
+
+	_, err = stockClient.GetStock(context.Background(), "IDX:BBCA")
+	require.Nil(t, err)
+	assert.Equal(t, gobreaker.StateClosed, breaker.State())
+}
+
+func TestCircuitBreakerOverride(t *testing.T) {
+	server, stockClient, breaker := newFakeStockClient(t)
+
+	breaker.SetOverride(circuit.OverrideOpen)
+	_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	assert.Empty(t, server.Requests(fakestock.StockPath))
+	assert.Equal(t, gobreaker.StateOpen, breaker.State())
+
+	breaker.SetOverride(circuit.OverrideClosed)
+	_, err = stockClient.GetStock(context.Background(), "IDX:BBCA")
+	require.Nil(t, err)
+	assert.Len(t, server.Requests(fakestock.StockPath), 1)
+
+	// Requests made while the breaker is forced are not counted
+	breaker.SetOverride(circuit.OverrideNone)
+	assert.Equal(t, gobreaker.StateClosed, breaker.State())
+	assert.Equal(t, uint32(0), breaker.Counts().Requests)
+}
+
+func TestCircuitBreakerPerExchange(t *testing.T) {
+	idx := fakestock.New()
+	t.Cleanup(idx.Close)
+	nasdaq := fakestock.New()
+	t.Cleanup(nasdaq.Close)
+	nasdaq.AddStocks(fakestock.Stock{Code: "AAPL", Name: "Apple Inc.", Sector: "Technology"})
+
+	breakers := map[string]*circuit.Breaker{}
+	routes := client.StockRoutes{entity.DefaultExchange: idx.URL, "NASDAQ": nasdaq.URL, "NYSE": nasdaq.URL}
+	stockClient := client.NewStockClient(routes, func(name string) client.Breaker {
+		breakers[name] = circuit.NewCircuitBreaker(t.Name()+"-"+name, config.CircuitBreaker{})
+		return breakers[name]
+	})
+
+	// NYSE shares the NASDAQ service and with it the breaker
+	require.Len(t, breakers, 2)
+	require.Contains(t, breakers, client.StockServiceBreaker)
+	require.Contains(t, breakers, client.BreakerName("NASDAQ"))
+
+	// An open IDX breaker leaves the NASDAQ service reachable
+	breakers[client.StockServiceBreaker].SetOverride(circuit.OverrideOpen)
+	_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	assert.Empty(t, idx.Requests(fakestock.StockPath))
+
+	stock, err := stockClient.GetStock(context.Background(), "NASDAQ:AAPL")
+	require.Nil(t, err)
+	assert.Equal(t, "Apple Inc.", stock.Name)
+	assert.Equal(t, gobreaker.StateClosed, breakers[client.BreakerName("NASDAQ")].State())
+}
+
+func TestGetCircuitBreakers(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetCircuitBreakersResponse](nil, adminCircuitPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	names := []string{}
+	for _, breaker := range result.Data {
+		names = append(names, breaker.Name)
+	}
+	assert.Contains(t, names, client.StockServiceBreaker)
+}
+
+func TestSetCircuitOverride(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := adminCircuitPath + "/" + client.StockServiceBreaker + "/override"
+	requestBody := request.SetCircuitOverrideRequest{Override: "open"}
+	result, statusCode, err := PerformRequest[*response.SetCircuitOverrideResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "open", result.Data.State)
+	assert.Equal(t, "open", result.Data.Override)
+
+	requestBody = request.SetCircuitOverrideRequest{Override: "none"}
+	result, statusCode, err = PerformRequest[*response.SetCircuitOverrideResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "closed", result.Data.State)
+	assert.Equal(t, "none", result.Data.Override)
+}
+
+func TestSetCircuitOverrideInvalid(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := adminCircuitPath + "/" + client.StockServiceBreaker + "/override"
+	requestBody := request.SetCircuitOverrideRequest{Override: "half-open"}
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrCircuitOverrideInvalid.Error(), result.Message)
+
+	url = adminCircuitPath + "/payment-service/override"
+	requestBody = request.SetCircuitOverrideRequest{Override: "open"}
+	result, statusCode, err = PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrCircuitBreakerNotFound.Error(), result.Message)
+}
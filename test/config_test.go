@@ -0,0 +1,150 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"stock_backend/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes content to a file in a directory removed after the test
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestConfigRequiresJWTSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	_, err := config.Load()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is required")
+}
+
+func TestConfigReportsEveryInvalidValue(t *testing.T) {
+	t.Setenv("REDIS_PORT", "six")
+	t.Setenv("CACHE_TIMEOUT", "soon")
+	t.Setenv("TRACING_EXPORTER", "jaeger")
+	t.Setenv("STOCK_SERVICE_URLS", "NASDAQ")
+
+	_, err := config.Load()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), `REDIS_PORT must be a number, got "six"`)
+	assert.Contains(t, err.Error(), `CACHE_TIMEOUT must be a duration such as 30s, got "soon"`)
+	assert.Contains(t, err.Error(), "STOCK_SERVICE_URLS entries must look like EXCHANGE=URL")
+	assert.Contains(t, err.Error(), `TRACING_EXPORTER must be one of none, otlp, stdout, got "jaeger"`)
+}
+
+func TestConfigValidateReportsEveryProblem(t *testing.T) {
+	cfg, err := config.Load()
+	require.Nil(t, err)
+
+	cfg.JWT.Secret = ""
+	cfg.Tracing.Exporter = "jaeger"
+	cfg.Workers.AlertInterval = 0
+	cfg.App.MetricsPort = cfg.App.Port
+
+	err = cfg.Validate()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is required")
+	assert.Contains(t, err.Error(), `TRACING_EXPORTER must be one of none, otlp, stdout, got "jaeger"`)
+	assert.Contains(t, err.Error(), "ALERT_WORKER_INTERVAL must be positive")
+	assert.Contains(t, err.Error(), "METRICS_PORT must differ from APP_PORT")
+}
+
+func TestConfigReadsSecretFiles(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt_secret", "secret-from-file\n"))
+
+	cfg, err := config.Load()
+	require.Nil(t, err)
+	assert.Equal(t, "secret-from-file", cfg.JWT.Secret)
+}
+
+func TestConfigRejectsValueAndFile(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt_secret", "secret-from-file"))
+
+	_, err := config.Load()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET and JWT_SECRET_FILE are both set")
+}
+
+func TestConfigLoadsYAML(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("WATCHLIST_CACHE_TTL", "")
+	t.Setenv("STOCK_SERVICE_URLS", "")
+	t.Setenv("HEALTH_CACHE_TTL", "9s")
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", `
+jwt:
+  secret: secret-from-yaml
+cache:
+  watchlist_ttl: 2m
+health:
+  cache_ttl: 1s
+stock_service:
+  urls:
+    nasdaq: http://us-stocks
+  symbol_formats:
+    HKEX: ^[0-9]{4,5}$
+circuit_breakers:
+  stock-service-yaml-test:
+    timeout: 45s
+    min_requests: 10
+`))
+
+	cfg, err := config.Load()
+	require.Nil(t, err)
+	assert.Equal(t, "secret-from-yaml", cfg.JWT.Secret)
+	assert.Equal(t, 2*time.Minute, cfg.Cache.WatchlistTTL)
+	assert.Equal(t, map[string]string{"nasdaq": "http://us-stocks"}, cfg.StockService.URLs)
+	assert.Equal(t, config.CircuitBreaker{Timeout: 45 * time.Second, MinRequests: 10}, cfg.CircuitBreaker("stock-service-yaml-test"))
+
+	// Configured symbol formats add to the defaults
+	assert.Equal(t, `^[0-9]{4,5}$`, cfg.StockService.SymbolFormats["HKEX"])
+	assert.Contains(t, cfg.StockService.SymbolFormats, "IDX")
+
+	// The environment overrides the file
+	assert.Equal(t, 9*time.Second, cfg.Health.CacheTTL)
+}
+
+func TestConfigRejectsUnknownYAMLKeys(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "cache:\n  watchlist_tll: 2m\n"))
+
+	_, err := config.Load()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "watchlist_tll")
+}
+
+func TestConfigReadsCircuitBreakersAndSymbolFormats(t *testing.T) {
+	t.Setenv("CIRCUIT_STOCK_SERVICE_CONFIG_TEST_TIMEOUT", "1m")
+	t.Setenv("CIRCUIT_STOCK_SERVICE_CONFIG_TEST_FAILURE_RATIO", "0.25")
+	t.Setenv("SYMBOL_FORMATS", "HKEX=^[0-9]{4,5}$;IDX=^[A-Z]{4}$")
+
+	cfg, err := config.Load()
+	require.Nil(t, err)
+	assert.Equal(t, config.CircuitBreaker{Timeout: time.Minute, FailureRatio: 0.25}, cfg.CircuitBreaker("stock-service-config-test"))
+	assert.Equal(t, `^[0-9]{4,5}$`, cfg.StockService.SymbolFormats["HKEX"])
+	assert.Equal(t, `^[A-Z]{4}$`, cfg.StockService.SymbolFormats["IDX"])
+	assert.Equal(t, config.Default().StockService.SymbolFormats["NASDAQ"], cfg.StockService.SymbolFormats["NASDAQ"])
+}
+
+func TestConfigRejectsInvalidCircuitBreakersAndSymbolFormats(t *testing.T) {
+	t.Setenv("CIRCUIT_STOCK_SERVICE_CONFIG_TEST_FAILURE_RATIO", "1.5")
+	t.Setenv("CIRCUIT_STOCK_SERVICE_CONFIG_TEST_MAX_REQUESTS", "-1")
+	t.Setenv("CIRCUIT_STOCK_SERVICE_CONFIG_TEST_TIMOUT", "1m")
+	t.Setenv("SYMBOL_FORMATS", "HKEX=^([0-9]$;SGX")
+
+	_, err := config.Load()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CIRCUIT_STOCK_SERVICE_CONFIG_TEST_FAILURE_RATIO must be between 0 and 1, got 1.5")
+	assert.Contains(t, err.Error(), "CIRCUIT_STOCK_SERVICE_CONFIG_TEST_MAX_REQUESTS must not be negative, got -1")
+	assert.Contains(t, err.Error(), "CIRCUIT_STOCK_SERVICE_CONFIG_TEST_TIMOUT is not a circuit breaker setting")
+	assert.Contains(t, err.Error(), `SYMBOL_FORMATS entries must look like EXCHANGE=regex, got "SGX"`)
+	assert.Contains(t, err.Error(), "SYMBOL_FORMATS pattern of HKEX does not compile")
+}
@@ -0,0 +1,289 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"stock_backend/internal/mailer"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/worker"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminEmailsPath = "/api/v1/admin/emails"
+
+func getVerificationEmailId(t *testing.T) int64 {
+	var emailId int64
+	err := db.QueryRow("SELECT id FROM email_outbox WHERE recipient = $1 ORDER BY id DESC LIMIT 1", email).Scan(&emailId)
+	require.Nil(t, err)
+	return emailId
+}
+
+func TestGetEmails(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := adminEmailsPath + "?status=pending"
+	result, statusCode, err := PerformRequest[*response.GetEmailsResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	recipients := []string{}
+	for _, email := range result.Data {
+		recipients = append(recipients, email.Recipient)
+	}
+	assert.Contains(t, recipients, email)
+}
+
+func TestGetEmailsInvalidStatus(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := adminEmailsPath + "?status=bounced"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrEmailStatusInvalid.Error(), result.Message)
+}
+
+func TestGetEmailsForbidden(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, adminEmailsPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, domainerr.ErrUnauthorizedAccess.Error(), result.Message)
+}
+
+func TestRetryEmailNotRetryable(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%d/retry", adminEmailsPath, getVerificationEmailId(t))
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusConflict, statusCode)
+	assert.Equal(t, domainerr.ErrEmailNotRetryable.Error(), result.Message)
+}
+
+func TestRetryEmail(t *testing.T) {
+	emailId := getVerificationEmailId(t)
+	_, err := db.Exec("UPDATE email_outbox SET status = 'failed', attempts = 5 WHERE id = $1", emailId)
+	require.Nil(t, err)
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%d/retry", adminEmailsPath, emailId)
+	result, statusCode, err := PerformRequest[*response.RetryEmailResponse](nil, url, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, fmt.Sprintf("Email %d queued for retry", emailId), result.Message)
+
+	var status string
+	var attempts int
+	err = db.QueryRow("SELECT status, attempts FROM email_outbox WHERE id = $1", emailId).Scan(&status, &attempts)
+	require.Nil(t, err)
+	assert.Equal(t, "pending", status)
+	assert.Equal(t, 0, attempts)
+}
+
+func TestRetryEmailNotFound(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%d/retry", adminEmailsPath, 999999999)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrEmailNotFound.Error(), result.Message)
+}
+
+func TestMessageBytes(t *testing.T) {
+	message := &mailer.Message{
+		From:            mail.Address{Name: "Stock App", Address: "noreply@stock.example.com"},
+		To:              mail.Address{Address: "user@example.com"},
+		Subject:         "Verifikasi akun — Stock App",
+		Text:            "Buka tautan berikut",
+		HTML:            "<p>Buka tautan berikut</p>",
+		ListUnsubscribe: "https://stock.example.com/unsubscribe",
+	}
+
+	raw, err := message.Bytes()
+	require.Nil(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.Nil(t, err)
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(parsed.Header.Get("Subject"))
+	require.Nil(t, err)
+
+	assert.Equal(t, message.Subject, subject)
+	assert.True(t, strings.HasPrefix(parsed.Header.Get("Subject"), "=?utf-8?q?"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@stock.example.com>"))
+	assert.NotEmpty(t, parsed.Header.Get("Date"))
+	assert.Equal(t, "<https://stock.example.com/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+	assert.True(t, strings.HasPrefix(parsed.Header.Get("Content-Type"), "multipart/alternative"))
+	assert.Contains(t, string(raw), "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, string(raw), "Content-Type: text/html; charset=UTF-8")
+}
+
+func TestRegisterLocalizedEmail(t *testing.T) {
+	requestBody := request.RegisterRequest{
+		Email:    "test_id@gmail.com",
+		Password: password,
+		Username: "test_username_id",
+		Locale:   "id",
+	}
+
+	httpHeader := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	_, statusCode, err := PerformRequest[*response.RegisterResponse](requestBody, registerPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	var subject, textBody string
+	err = db.QueryRow("SELECT subject, text_body FROM email_outbox WHERE recipient = $1", requestBody.Email).Scan(&subject, &textBody)
+	require.Nil(t, err)
+
+	assert.Equal(t, "Verifikasi akun Stock App Anda", subject)
+	assert.Contains(t, textBody, "/api/v1/auth/verify?token=")
+}
+
+var verifyURLPattern = regexp.MustCompile(`https?://\S+/api/v1/auth/verify\?token=\S+`)
+
+func TestDispatchVerificationEmail(t *testing.T) {
+	requestBody := request.RegisterRequest{
+		Email:    "test_dispatch@gmail.com",
+		Password: password,
+		Username: "test_username_dispatch",
+	}
+
+	httpHeader := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	_, statusCode, err := PerformRequest[*response.RegisterResponse](requestBody, registerPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	sender := mailer.NewMemorySender(mail.Address{Name: "Stock App", Address: "noreply@stock.example.com"})
+	dispatcher := worker.NewEmailDispatcher(repository.NewEmailOutboxRepository(db), sender, time.Second)
+	require.Nil(t, dispatcher.DispatchOnce(context.Background()))
+
+	message, ok := sender.LastTo(requestBody.Email)
+	require.True(t, ok)
+	assert.Equal(t, "noreply@stock.example.com", message.From.Address)
+
+	verifyURL, err := url.Parse(verifyURLPattern.FindString(message.Text))
+	require.Nil(t, err)
+
+	result, statusCode, err := PerformRequest[*response.VerifyResponse](nil, verifyURL.RequestURI(), http.MethodGet, map[string]string{"Accept": "application/json"})
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "User verified successfully", result.Message)
+}
+
+// stoppingSender cancels the dispatch after its first email, as a shutdown would
+type stoppingSender struct {
+	*mailer.MemorySender
+	cancel context.CancelFunc
+}
+
+func (sender *stoppingSender) Send(ctx context.Context, message *mailer.Message) error {
+	sender.cancel()
+	return sender.MemorySender.Send(ctx, message)
+}
+
+func TestDispatchReleasesUnsentEmails(t *testing.T) {
+	for i := range 3 {
+		_, err := db.Exec("INSERT INTO email_outbox (recipient, subject, text_body, html_body) VALUES ($1, 'Shutdown', 'Hello', '')",
+			fmt.Sprintf("shutdown_%d@example.com", i))
+		require.Nil(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sender := &stoppingSender{
+		MemorySender: mailer.NewMemorySender(mail.Address{Address: "noreply@stock.example.com"}),
+		cancel:       cancel,
+	}
+	dispatcher := worker.NewEmailDispatcher(repository.NewEmailOutboxRepository(db), sender, time.Second)
+	assert.ErrorIs(t, dispatcher.DispatchOnce(ctx), context.Canceled)
+
+	// The email in flight was sent, the rest of the batch can be claimed again right away
+	assert.Len(t, sender.Messages(), 1)
+
+	var locked int
+	err := db.QueryRow("SELECT COUNT(*) FROM email_outbox WHERE status = 'pending' AND locked_until IS NOT NULL").Scan(&locked)
+	require.Nil(t, err)
+	assert.Equal(t, 0, locked)
+
+	_, err = db.Exec("DELETE FROM email_outbox WHERE subject = 'Shutdown'")
+	require.Nil(t, err)
+}
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := mailer.NewFileSender(dir, mail.Address{Name: "Stock App", Address: "noreply@stock.example.com"})
+	require.Nil(t, err)
+
+	err = sender.Send(context.Background(), &mailer.Message{
+		To:      mail.Address{Address: "user@example.com"},
+		Subject: "Hello",
+		Text:    "Hello from the file sink",
+	})
+	require.Nil(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.Nil(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.Nil(t, err)
+	assert.Equal(t, "\"Stock App\" <noreply@stock.example.com>", parsed.Header.Get("From"))
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+}
@@ -53,6 +53,25 @@ func TestAddFavoritesDuplicates(t *testing.T) {
 	assert.Equal(t, domainerr.ErrFavoritesDuplicate.Error(), result.Message)
 }
 
+func TestAddFavoritesUnknownUnderwriter(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.AddFavoriteUnderwriterRequest{
+		UnderwriterId: "ZZ",
+	}
+
+	url := favoritesPath
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPost, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.Equal(t, domainerr.ErrUnderwriterNotFound.Error(), result.Message)
+}
+
 func TestAddFavoritesUnauthorized(t *testing.T) {
 	httpHeader := map[string]string{
 		"Content-Type": "application/json",
@@ -83,7 +102,8 @@ func TestGetFavorites(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, statusCode)
 	assert.Equal(t, "Favorite Found", result.Message)
-	assert.Equal(t, "KI", result.Data[0])
+	assert.Equal(t, "KI", result.Data[0].ID)
+	assert.Equal(t, "Ciptadana Sekuritas Asia", result.Data[0].Name)
 }
 
 func TestGetFavoritesUnauthorized(t *testing.T) {
@@ -170,3 +190,106 @@ func TestGetFavoritesNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, statusCode)
 	assert.Equal(t, domainerr.ErrFavoritesNotFound.Error(), result.Message)
 }
+
+func TestImportFavoritesJSON(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := map[string][]string{"underwriters": {"KI", "yp", "ZZ", "KI"}}
+	result, statusCode, err := PerformRequest[*response.ImportResponse](requestBody, favoritesPath+"/import", http.MethodPost, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, "YP", result.Rows[1].Code)
+	assert.Equal(t, domainerr.ErrUnderwriterNotFound.Error(), result.Rows[2].Error)
+	assert.Equal(t, domainerr.ErrImportDuplicateRow.Error(), result.Rows[3].Error)
+}
+
+func TestExportFavoritesJSON(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[map[string][]string](nil, favoritesPath+"/export?format=json", http.MethodGet, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []string{"KI", "YP"}, result["underwriters"])
+}
+
+func TestImportFavoritesInvalidCSV(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "text/csv",
+		"Accept":        "application/json",
+	}
+
+	body, res, err := PerformRawRequest("underwriter_id\n\"KI\n", favoritesPath+"/import", http.MethodPost, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Contains(t, string(body), domainerr.ErrImportInvalidFormat.Error())
+}
+
+func TestAddFavoritesBatch(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.BatchFavoriteRequest{
+		UnderwriterIds: []string{"CC", "KI", "ZZ"},
+	}
+
+	result, statusCode, err := PerformRequest[*response.BatchResponse](requestBody, favoritesPath+":batch", http.MethodPost, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, response.BatchItemAdded, result.Items[0].Status)
+	assert.Equal(t, domainerr.ErrFavoritesDuplicate.Error(), result.Items[1].Error)
+	assert.Equal(t, domainerr.ErrUnderwriterNotFound.Error(), result.Items[2].Error)
+}
+
+func TestRemoveFavoritesBatch(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.BatchFavoriteRequest{
+		UnderwriterIds: []string{"CC", "KI", "YP", "AK"},
+	}
+
+	result, statusCode, err := PerformRequest[*response.BatchResponse](requestBody, favoritesPath+":batch", http.MethodDelete, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Equal(t, domainerr.ErrFavoritesNotFound.Error(), result.Items[3].Error)
+}
+
+func TestRemoveFavoritesBatchUnauthorized(t *testing.T) {
+	httpHeader := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	requestBody := request.BatchFavoriteRequest{
+		UnderwriterIds: []string{"KI"},
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, favoritesPath+":batch", http.MethodDelete, httpHeader)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Equal(t, domainerr.ErrAuthorizationHeaderRequired.Error(), result.Message)
+}
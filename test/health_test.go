@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"stock_backend/internal/health"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/response"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveness(t *testing.T) {
+	result, statusCode, err := PerformRequest[*response.LivenessResponse](nil, "/healthz", http.MethodGet, nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "OK", result.Message)
+}
+
+func TestReadiness(t *testing.T) {
+	result, statusCode, err := PerformRequest[*response.ReadinessResponse](nil, "/readyz", http.MethodGet, nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, result.Data.Ready)
+
+	statuses := map[string]string{}
+	for _, check := range result.Data.Checks {
+		statuses[check.Name] = check.Status
+		assert.Empty(t, check.Error)
+	}
+	assert.Equal(t, health.StatusUp, statuses["postgres"])
+	assert.Equal(t, health.StatusUp, statuses["redis"])
+	assert.Equal(t, health.StatusUp, statuses["stock_service"])
+}
+
+func TestHealthDetails(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + adminToken,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetHealthDetailsResponse](nil, "/health/details", http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, result.Data.Ready)
+	assert.NotEmpty(t, result.Data.Checks)
+	assert.NotEmpty(t, result.Data.CircuitBreakers)
+	assert.Greater(t, result.Data.Database.OpenConnections, 0)
+	assert.Greater(t, result.Data.Redis.TotalConns, uint32(0))
+	assert.False(t, result.Data.Cache.Degraded)
+}
+
+func TestHealthDetailsForbidden(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, "/health/details", http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, domainerr.ErrUnauthorizedAccess.Error(), result.Message)
+}
+
+func TestHealthCheckerCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	checker := health.NewChecker(time.Second, time.Minute, health.Check{
+		Name:     "counter",
+		Critical: true,
+		Ping: func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		results := checker.Run(context.Background())
+		assert.True(t, health.Ready(results))
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHealthCheckerTimesOutSlowChecks(t *testing.T) {
+	checker := health.NewChecker(50*time.Millisecond, 0,
+		health.Check{
+			Name:     "slow",
+			Critical: true,
+			Ping: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+		health.Check{
+			Name: "fast",
+			Ping: func(ctx context.Context) error { return nil },
+		},
+	)
+
+	start := time.Now()
+	results := checker.Run(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, health.StatusDown, results[0].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), results[0].Error)
+	assert.Equal(t, health.StatusUp, results[1].Status)
+	assert.False(t, health.Ready(results))
+}
+
+func TestHealthCheckerOptionalCheckDown(t *testing.T) {
+	checker := health.NewChecker(time.Second, 0,
+		health.Check{
+			Name:     "database",
+			Critical: true,
+			Ping:     func(ctx context.Context) error { return nil },
+		},
+		health.Check{
+			Name: "mail",
+			Ping: func(ctx context.Context) error { return errors.New("connection refused") },
+		},
+	)
+
+	results := checker.Run(context.Background())
+	assert.True(t, health.Ready(results))
+	assert.Equal(t, health.StatusDown, results[1].Status)
+}
@@ -126,3 +126,31 @@ func PerformRequest[T any](requestBody any, url string, httpMethod string, httpH
 
 	return result, res.StatusCode, nil
 }
+
+func GetUserIDByEmail(email string) (string, error) {
+	var userId string
+	err := db.QueryRow("SELECT id FROM users WHERE email = $1", email).Scan(&userId)
+	return userId, err
+}
+
+// PerformRawRequest sends a non-JSON body and returns the raw response
+func PerformRawRequest(body string, url string, httpMethod string, httpHeader map[string]string) ([]byte, *http.Response, error) {
+	req := httptest.NewRequest(httpMethod, url, strings.NewReader(body))
+	for key, val := range httpHeader {
+		req.Header.Set(key, val)
+	}
+
+	res, err := app.Test(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err = res.Body.Close(); err != nil {
+			log.Println("failed to close body")
+		}
+	}()
+
+	bytes, err := io.ReadAll(res.Body)
+	return bytes, res, err
+}
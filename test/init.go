@@ -1,15 +1,36 @@
 package test
 
 import (
+	"context"
 	"database/sql"
+	"net/mail"
+	"os"
 	"stock_backend/config"
+	"stock_backend/internal/cache"
+	"stock_backend/internal/client/fakestock"
 	"stock_backend/internal/delivery/router"
+	"stock_backend/internal/mailer"
+	"stock_backend/internal/tracing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 var app *fiber.App
+var metricsApp *fiber.App
+var appConfig *config.Config
 var db *sql.DB
+var redisDb *redis.Client
+var redisCache *cache.Cache
+
+// spanExporter keeps every finished span so tests can assert on the traces
+var spanExporter = tracetest.NewInMemoryExporter()
+
+// stockServer stands in for the Stock Backend so the tests do not depend on it
+var stockServer *fakestock.Server
 
 const email = "richardsugiharto0@gmail.com"
 const password = "87654321"
@@ -20,7 +41,44 @@ var adminToken string
 func init() {
 	config.LoadEnv("../test.env")
 
-	db = config.DatabaseConfig()
-	redisDb := config.ConnectRedis()
-	app = router.SetupRouter(db, redisDb)
+	if _, err := tracing.NewProvider(context.Background(), sdktrace.WithSyncer(spanExporter)); err != nil {
+		panic(err)
+	}
+
+	stockServer = fakestock.New()
+	stockServer.AddStocks(
+		fakestock.Stock{Code: "BBCA", Name: "Bank Central Asia Tbk.", Sector: "Financials"},
+		fakestock.Stock{Code: "NOBU", Name: "Bank Nationalnobu Tbk.", Sector: "Financials"},
+		fakestock.Stock{Code: "TLKM", Name: "Telkom Indonesia (Persero) Tbk.", Sector: "Infrastructures"},
+		fakestock.Stock{Code: "ASII", Name: "Astra International Tbk.", Sector: "Industrials"},
+	)
+	stockServer.SetQuotes(
+		fakestock.Quote{Code: "BBCA", LastPrice: 9500, Change: 125, ChangePercent: 1.33, Volume: 52000000},
+		fakestock.Quote{Code: "NOBU", LastPrice: 525, Change: -5, ChangePercent: -0.94, Volume: 310000},
+		fakestock.Quote{Code: "TLKM", LastPrice: 3100, Change: 20, ChangePercent: 0.65, Volume: 88000000},
+		fakestock.Quote{Code: "ASII", LastPrice: 5200, Change: 0, ChangePercent: 0, Volume: 21000000},
+	)
+	stockServer.AddUnderwriters(
+		fakestock.Underwriter{Code: "YP", Name: "Mirae Asset Sekuritas Indonesia"},
+		fakestock.Underwriter{Code: "CC", Name: "Mandiri Sekuritas"},
+	)
+	if err := os.Setenv("STOCK_SERVICE_URL", stockServer.URL); err != nil {
+		panic(err)
+	}
+	// Emails are kept in memory, the tests need no SMTP server
+	if err := os.Setenv("EMAIL_TRANSPORT", mailer.TransportMemory); err != nil {
+		panic(err)
+	}
+
+	var err error
+	appConfig, err = config.Load()
+	if err != nil {
+		panic(err)
+	}
+
+	db = config.DatabaseConfig(appConfig.Database)
+	redisDb = config.ConnectRedis(appConfig.Redis)
+	redisCache = cache.New(redisDb, 200*time.Millisecond, time.Second)
+	app = router.SetupRouter(appConfig, db, redisCache, mailer.NewMemorySender(mail.Address{Address: "noreply@stock.example.com"}), router.NewStockCache(appConfig, redisCache))
+	metricsApp = router.SetupMetricsRouter(db)
 }
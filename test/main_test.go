@@ -7,6 +7,7 @@ import (
 
 func TestMain(m *testing.M) {
 	ClearTable("users")
+	ClearTable("email_outbox")
 	if err := CreateTestUser(email, password); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stock_backend/internal/client/fakestock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics returns the text exposition served on /metrics of the metrics app
+func scrapeMetrics(t *testing.T) string {
+	res, err := metricsApp.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Nil(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	body, err := io.ReadAll(res.Body)
+	require.Nil(t, err)
+	return string(body)
+}
+
+func TestMetricsNotServedByAPI(t *testing.T) {
+	_, res, err := PerformRawRequest("", "/metrics", http.MethodGet, nil)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestMetricsHTTPRequests(t *testing.T) {
+	_, _, err := PerformRawRequest("", "/healthz", http.MethodGet, nil)
+	require.Nil(t, err)
+	_, _, err = PerformRawRequest("", "/api/v1/does-not-exist/42", http.MethodGet, nil)
+	require.Nil(t, err)
+
+	metrics := scrapeMetrics(t)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/healthz",status="200"}`)
+	assert.Contains(t, metrics, `http_request_duration_seconds_count{method="GET",route="/healthz",status="200"}`)
+	assert.Contains(t, metrics, `route="unmatched"`)
+	assert.NotContains(t, metrics, "does-not-exist")
+}
+
+func TestMetricsDependencies(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	for range 2 {
+		_, res, err := PerformRawRequest("", favoritesPath, http.MethodGet, httpHeader)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	metrics := scrapeMetrics(t)
+	assert.Contains(t, metrics, `go_sql_open_connections{db_name="postgres"}`)
+	assert.Contains(t, metrics, `redis_command_duration_seconds_count{command="get",status="ok"}`)
+	assert.Contains(t, metrics, `cache_lookups_total{cache="favorites",result="hit"}`)
+	assert.Contains(t, metrics, `cache_lookups_total{cache="favorites",result="miss"}`)
+	assert.Contains(t, metrics, `circuit_breaker_state{name=`)
+}
+
+func TestMetricsStockClientRetries(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+	server.Script(fakestock.StockPath, fakestock.Step{Status: http.StatusServiceUnavailable})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	require.Nil(t, err)
+
+	metrics := scrapeMetrics(t)
+	assert.Contains(t, metrics, `stock_client_requests_total{endpoint="stock",outcome="success"}`)
+	assert.Contains(t, metrics, `stock_client_retries_total{endpoint="stock"}`)
+	assert.Contains(t, metrics, `stock_client_request_duration_seconds_count{endpoint="stock"}`)
+}
@@ -2,12 +2,16 @@ package test
 
 import (
 	"net/http"
+	"stock_backend/internal/helper"
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/request"
 	"stock_backend/internal/model/response"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUserRole(t *testing.T) {
@@ -75,3 +79,31 @@ func TestLoggedOutFailed(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, statusCode)
 	assert.Equal(t, domainerr.ErrUserLoggedIn.Error(), result.Message)
 }
+
+func TestRequestIDKeepsValidHeader(t *testing.T) {
+	for _, requestId := range []string{
+		"0b6f3f8e-3c55-4b7e-9d7a-2f1f4a3b5c6d",
+		"gateway.request_1-a",
+		strings.Repeat("a", 64),
+	} {
+		_, res, err := PerformRawRequest("", "/healthz", http.MethodGet, map[string]string{helper.RequestIDHeader: requestId})
+		require.Nil(t, err)
+		assert.Equal(t, requestId, res.Header.Get(helper.RequestIDHeader))
+	}
+}
+
+func TestRequestIDReplacesInvalidHeader(t *testing.T) {
+	for _, requestId := range []string{
+		strings.Repeat("a", 65),
+		"request id",
+		"<script>alert(1)</script>",
+		`{"level":"ERROR"}`,
+	} {
+		_, res, err := PerformRawRequest("", "/healthz", http.MethodGet, map[string]string{helper.RequestIDHeader: requestId})
+		require.Nil(t, err)
+
+		generated := res.Header.Get(helper.RequestIDHeader)
+		assert.NotEqual(t, requestId, generated)
+		assert.Nil(t, uuid.Validate(generated))
+	}
+}
@@ -0,0 +1,385 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"stock_backend/internal/notifier"
+	"stock_backend/internal/repository"
+	"stock_backend/internal/worker"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	notificationPath = "/api/v1/notifications"
+	deviceToken      = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+)
+
+var notificationId string
+
+func TestAPNsChannelHTTP2(t *testing.T) {
+	var received struct {
+		proto         int
+		path          string
+		topic         string
+		authorization string
+		payload       map[string]any
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.proto = r.ProtoMajor
+		received.path = r.URL.Path
+		received.topic = r.Header.Get("apns-topic")
+		received.authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received.payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	signer := notifier.NewAPNsTokenSigner(key, "KEY123", "TEAM123")
+	channel := notifier.NewAPNsChannel(server.Client(), server.URL, "com.example.stock", signer)
+
+	err = channel.Send(context.Background(), deviceToken, notifier.Message{
+		Kind:  "alert_triggered",
+		Title: "BBCA price alert",
+		Body:  "BBCA is at 10000.00",
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, 2, received.proto)
+	assert.Equal(t, "/3/device/"+deviceToken, received.path)
+	assert.Equal(t, "com.example.stock", received.topic)
+	assert.True(t, strings.HasPrefix(received.authorization, "bearer "))
+	assert.Equal(t, "alert_triggered", received.payload["kind"])
+}
+
+func TestAPNsChannelRejected(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	channel := notifier.NewAPNsChannel(server.Client(), server.URL, "com.example.stock", nil)
+	err := channel.Send(context.Background(), deviceToken, notifier.Message{Title: "title", Body: "body"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "BadDeviceToken")
+}
+
+func TestWebhookClientBlocksInternalAddresses(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	channel := notifier.NewWebhookChannel(notifier.NewWebhookHTTPClient(time.Second), "")
+	err := channel.Send(context.Background(), server.URL, notifier.Message{Title: "title", Body: "body"})
+	assert.ErrorIs(t, err, notifier.ErrWebhookAddressBlocked)
+
+	// A hostname resolving to loopback is caught when it is dialed
+	err = channel.Send(context.Background(), strings.Replace(server.URL, "127.0.0.1", "localhost", 1), notifier.Message{Title: "title", Body: "body"})
+	assert.ErrorIs(t, err, notifier.ErrWebhookAddressBlocked)
+	assert.False(t, received)
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	httpClient := notifier.NewWebhookHTTPClient(time.Second)
+	require.NotNil(t, httpClient.CheckRedirect)
+	assert.Equal(t, http.ErrUseLastResponse, httpClient.CheckRedirect(nil, nil))
+}
+
+func TestUpdateNotificationPreferenceRejectsInternalWebhook(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	enabled := true
+	for _, target := range []string{
+		"http://hooks.example.com/stock",
+		"https://localhost/stock",
+		"https://127.0.0.1/stock",
+		"https://10.0.0.5/stock",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/stock",
+		"https://0.0.0.0/stock",
+	} {
+		requestBody := request.UpdateNotificationPreferenceRequest{
+			Enabled: &enabled,
+			Target:  target,
+		}
+
+		url := notificationPath + "/preferences/webhook"
+		result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPut, httpHeader)
+		require.Nil(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode, target)
+		assert.Equal(t, domainerr.ErrNotificationTargetInvalid.Error(), result.Message, target)
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	channel := notifier.NewInAppChannel(repository.NewNotificationRepository(db))
+	err = channel.Send(context.Background(), userId, notifier.Message{
+		Kind:  "alert_triggered",
+		Title: "BBCA price alert",
+		Body:  "BBCA is at 10000.00, above your alert at 9900.00",
+	})
+	require.Nil(t, err)
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "?unread=true"
+	result, statusCode, err := PerformRequest[*response.GetNotificationsResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, 1, result.Unread)
+	assert.False(t, result.Data[0].Read)
+	notificationId = result.Data[0].ID.String()
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/read", notificationPath, notificationId)
+	result, statusCode, err := PerformRequest[*response.MarkNotificationResponse](nil, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Notification marked as read", result.Message)
+
+	url = notificationPath + "?unread=true"
+	listResult, statusCode, err := PerformRequest[*response.GetNotificationsResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, listResult.Data)
+}
+
+func TestMarkNotificationUnread(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/unread", notificationPath, notificationId)
+	result, statusCode, err := PerformRequest[*response.MarkNotificationResponse](nil, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Notification marked as unread", result.Message)
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "/read-all"
+	result, statusCode, err := PerformRequest[*response.MarkNotificationResponse](nil, url, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "All notifications marked as read", result.Message)
+}
+
+func TestMarkNotificationReadNotFound(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/read", notificationPath, "00000000-0000-0000-0000-000000000000")
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrNotificationNotFound.Error(), result.Message)
+}
+
+func TestUpdateNotificationPreferenceTargetRequired(t *testing.T) {
+	enabled := true
+	requestBody := request.UpdateNotificationPreferenceRequest{
+		Enabled: &enabled,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "/preferences/webhook"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrNotificationTargetRequired.Error(), result.Message)
+}
+
+func TestUpdateNotificationPreference(t *testing.T) {
+	enabled := true
+	requestBody := request.UpdateNotificationPreferenceRequest{
+		Enabled: &enabled,
+		Target:  "https://hooks.example.com/stock",
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "/preferences/webhook"
+	result, statusCode, err := PerformRequest[*response.UpdateNotificationPreferenceResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "webhook", result.Data.Channel)
+
+	url = notificationPath + "/preferences"
+	listResult, statusCode, err := PerformRequest[*response.GetNotificationPreferencesResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, listResult.Data, 1)
+	assert.Equal(t, "https://hooks.example.com/stock", listResult.Data[0].Target)
+}
+
+func TestUpdateNotificationPreferenceRejectsForeignEmail(t *testing.T) {
+	enabled := true
+	requestBody := request.UpdateNotificationPreferenceRequest{
+		Enabled: &enabled,
+		Target:  "someone-else@example.com",
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "/preferences/email"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrNotificationEmailNotOwned.Error(), result.Message)
+}
+
+func TestUpdateNotificationPreferenceAccountEmail(t *testing.T) {
+	enabled := true
+	requestBody := request.UpdateNotificationPreferenceRequest{
+		Enabled: &enabled,
+		Target:  strings.ToUpper(email),
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	url := notificationPath + "/preferences/email"
+	result, statusCode, err := PerformRequest[*response.UpdateNotificationPreferenceResponse](requestBody, url, http.MethodPut, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "email", result.Data.Channel)
+
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	_, err = db.Exec("DELETE FROM notification_preferences WHERE userid = $1 AND channel = 'email'", userId)
+	require.Nil(t, err)
+}
+
+// stoppingChannel cancels the dispatch after its first delivery, as a shutdown would
+type stoppingChannel struct {
+	cancel  context.CancelFunc
+	targets []string
+}
+
+func (channel *stoppingChannel) Name() string {
+	return entity.ChannelWebhook
+}
+
+func (channel *stoppingChannel) Send(ctx context.Context, target string, message notifier.Message) error {
+	channel.cancel()
+	channel.targets = append(channel.targets, target)
+	return nil
+}
+
+func TestDispatchReleasesUnsentDeliveries(t *testing.T) {
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	deliveryRepository := repository.NewNotificationDeliveryRepository(db)
+	deliveries := []entity.NotificationDelivery{}
+	for i := range 3 {
+		deliveries = append(deliveries, entity.NotificationDelivery{
+			UserID:  userId,
+			Channel: entity.ChannelWebhook,
+			Target:  fmt.Sprintf("https://hooks.example.com/shutdown/%d", i),
+			Kind:    "shutdown",
+			Title:   "Shutdown",
+			Body:    "Hello",
+		})
+	}
+	require.Nil(t, deliveryRepository.EnqueueDeliveries(context.Background(), deliveries))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	channel := &stoppingChannel{cancel: cancel}
+	notificationWorker := worker.NewNotificationWorker(deliveryRepository, time.Second, channel)
+	assert.ErrorIs(t, notificationWorker.DispatchOnce(ctx), context.Canceled)
+
+	// The delivery in flight was sent and recorded, the rest of the batch can be claimed again right away
+	assert.Len(t, channel.targets, 1)
+
+	var sent, locked int
+	err = db.QueryRow("SELECT COUNT(*) FILTER (WHERE status = 'sent'), COUNT(*) FILTER (WHERE status = 'pending' AND locked_until IS NOT NULL) FROM notification_deliveries WHERE kind = 'shutdown'").
+		Scan(&sent, &locked)
+	require.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 0, locked)
+
+	_, err = db.Exec("DELETE FROM notification_deliveries WHERE kind = 'shutdown'")
+	require.Nil(t, err)
+}
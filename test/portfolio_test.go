@@ -0,0 +1,245 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const portfolioPath = "/api/v1/portfolios"
+
+var (
+	portfolioId   string
+	firstBuyId    string
+	portfolioTime = time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+)
+
+func portfolioHeader() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+}
+
+func createTransaction(t *testing.T, requestBody request.CreateTransactionRequest) *response.CreateTransactionResponse {
+	url := fmt.Sprintf("%s/%s/transactions", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.CreateTransactionResponse](requestBody, url, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	return result
+}
+
+func TestCreatePortfolio(t *testing.T) {
+	requestBody := request.CreatePortfolioRequest{
+		Name:       "Long term",
+		CostMethod: "fifo",
+	}
+
+	result, statusCode, err := PerformRequest[*response.CreatePortfolioResponse](requestBody, portfolioPath, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "Long term", result.Data.Name)
+	assert.Equal(t, "fifo", result.Data.CostMethod)
+	portfolioId = result.Data.ID.String()
+}
+
+func TestCreatePortfolioDuplicate(t *testing.T) {
+	requestBody := request.CreatePortfolioRequest{
+		Name: "Long term",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, portfolioPath, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusConflict, statusCode)
+	assert.Equal(t, domainerr.ErrPortfolioDuplicate.Error(), result.Message)
+}
+
+func TestCreatePortfolioInvalidCostMethod(t *testing.T) {
+	requestBody := request.CreatePortfolioRequest{
+		Name:       "Trading",
+		CostMethod: "lifo",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, portfolioPath, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "CostMethod must be one of average fifo", result.Message)
+}
+
+func TestCreatePortfolioTransactions(t *testing.T) {
+	first := createTransaction(t, request.CreateTransactionRequest{
+		Stock:    "bbca",
+		Type:     "buy",
+		Quantity: decimal.NewFromInt(100),
+		Price:    decimal.NewFromInt(1000),
+		Fee:      decimal.NewFromInt(10),
+		TradedAt: &portfolioTime,
+	})
+	assert.Equal(t, "IDX:BBCA", first.Data.Stock)
+	firstBuyId = first.Data.ID.String()
+
+	secondTime := portfolioTime.Add(time.Hour)
+	createTransaction(t, request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "buy",
+		Quantity: decimal.NewFromInt(100),
+		Price:    decimal.NewFromInt(1300),
+		TradedAt: &secondTime,
+	})
+
+	splitTime := portfolioTime.Add(2 * time.Hour)
+	createTransaction(t, request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "split",
+		Ratio:    decimal.NewFromInt(2),
+		TradedAt: &splitTime,
+	})
+
+	sellTime := portfolioTime.Add(3 * time.Hour)
+	createTransaction(t, request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "sell",
+		Quantity: decimal.NewFromInt(300),
+		Price:    decimal.NewFromInt(700),
+		Fee:      decimal.NewFromInt(5),
+		TradedAt: &sellTime,
+	})
+
+	dividendTime := portfolioTime.Add(4 * time.Hour)
+	createTransaction(t, request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "dividend",
+		Amount:   decimal.RequireFromString("50.5"),
+		TradedAt: &dividendTime,
+	})
+}
+
+func TestCreatePortfolioTransactionOversell(t *testing.T) {
+	requestBody := request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "sell",
+		Quantity: decimal.NewFromInt(101),
+		Price:    decimal.NewFromInt(700),
+	}
+
+	url := fmt.Sprintf("%s/%s/transactions", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.Equal(t, domainerr.ErrPortfolioInsufficientShares.Error(), result.Message)
+}
+
+func TestCreatePortfolioTransactionInvalidPrice(t *testing.T) {
+	requestBody := request.CreateTransactionRequest{
+		Stock:    "IDX:BBCA",
+		Type:     "buy",
+		Quantity: decimal.NewFromInt(100),
+	}
+
+	url := fmt.Sprintf("%s/%s/transactions", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPost, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrPortfolioPriceInvalid.Error(), result.Message)
+}
+
+func TestGetPortfolioTransactions(t *testing.T) {
+	url := fmt.Sprintf("%s/%s/transactions?stock=BBCA", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.GetTransactionsResponse](nil, url, http.MethodGet, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 5)
+	assert.Equal(t, firstBuyId, result.Data[0].ID.String())
+	assert.Equal(t, "dividend", result.Data[4].Type)
+}
+
+func TestGetPortfolioHoldings(t *testing.T) {
+	url := fmt.Sprintf("%s/%s/holdings", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.GetHoldingsResponse](nil, url, http.MethodGet, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+
+	// FIFO sells the 200 split shares of the first buy and 100 of the second
+	holding := result.Data[0]
+	assert.Equal(t, "IDX:BBCA", holding.Stock)
+	assert.Equal(t, "100", holding.Quantity.String())
+	assert.Equal(t, "65000", holding.CostBasis.String())
+	assert.Equal(t, "650", holding.AverageCost.String())
+	assert.Equal(t, "44985", holding.RealizedPnL.String())
+	assert.Equal(t, "50.5", holding.Dividends.String())
+}
+
+func TestUpdatePortfolioCostMethod(t *testing.T) {
+	costMethod := "average"
+	requestBody := request.UpdatePortfolioRequest{
+		CostMethod: &costMethod,
+	}
+
+	url := fmt.Sprintf("%s/%s", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.UpdatePortfolioResponse](requestBody, url, http.MethodPatch, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, costMethod, result.Data.CostMethod)
+}
+
+func TestGetPortfolioSummary(t *testing.T) {
+	url := fmt.Sprintf("%s/%s/summary", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.GetPortfolioSummaryResponse](nil, url, http.MethodGet, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "average", result.Data.CostMethod)
+	assert.Equal(t, 1, result.Data.OpenPositions)
+	assert.Equal(t, "57502.5", result.Data.CostBasis.String())
+	assert.Equal(t, "37487.5", result.Data.RealizedPnL.String())
+	assert.Equal(t, "50.5", result.Data.Dividends.String())
+}
+
+func TestDeletePortfolioTransactionBreaksLedger(t *testing.T) {
+	url := fmt.Sprintf("%s/%s/transactions/%s", portfolioPath, portfolioId, firstBuyId)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.Equal(t, domainerr.ErrPortfolioInsufficientShares.Error(), result.Message)
+}
+
+func TestGetPortfolioInvalidId(t *testing.T) {
+	url := fmt.Sprintf("%s/%s", portfolioPath, "not-a-uuid")
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrPortfolioIdInvalid.Error(), result.Message)
+}
+
+func TestDeletePortfolio(t *testing.T) {
+	url := fmt.Sprintf("%s/%s", portfolioPath, portfolioId)
+	result, statusCode, err := PerformRequest[*response.DeletePortfolioResponse](nil, url, http.MethodDelete, portfolioHeader())
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Portfolio deleted successfully", result.Message)
+
+	_, statusCode, err = PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, portfolioHeader())
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
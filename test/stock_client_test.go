@@ -0,0 +1,218 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"stock_backend/config"
+	"stock_backend/internal/circuit"
+	"stock_backend/internal/client"
+	"stock_backend/internal/client/fakestock"
+	"stock_backend/internal/entity"
+	"stock_backend/internal/helper"
+	"stock_backend/internal/model/domainerr"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeStockClient starts a fake Stock Backend listing BBCA and TLKM and a client talking to it
+func newFakeStockClient(t *testing.T) (*fakestock.Server, client.StockClient, *circuit.Breaker) {
+	return newFakeStockClientWithBreaker(t, config.CircuitBreaker{})
+}
+
+// newFakeStockClientWithBreaker is newFakeStockClient with a tuned breaker
+func newFakeStockClientWithBreaker(t *testing.T, settings config.CircuitBreaker) (*fakestock.Server, client.StockClient, *circuit.Breaker) {
+	server := fakestock.New()
+	t.Cleanup(server.Close)
+
+	server.AddStocks(
+		fakestock.Stock{Code: "BBCA", Name: "Bank Central Asia Tbk.", Sector: "Financials"},
+		fakestock.Stock{Code: "TLKM", Name: "Telkom Indonesia (Persero) Tbk.", Sector: "Infrastructures"},
+	)
+	server.SetQuotes(fakestock.Quote{Code: "BBCA", LastPrice: 9500, Change: 125, ChangePercent: 1.33, Volume: 52000000})
+	server.AddUnderwriters(
+		fakestock.Underwriter{Code: "yp", Name: "Mirae Asset Sekuritas Indonesia"},
+		fakestock.Underwriter{Code: "CC", Name: "Mandiri Sekuritas"},
+	)
+
+	// Every exchange is routed to the same server, so they share one breaker
+	breaker := circuit.NewCircuitBreaker(t.Name(), settings)
+	stockClient := client.NewStockClient(server.Routes(), func(string) client.Breaker {
+		return breaker
+	})
+	return server, stockClient, breaker
+}
+
+func TestStockClientGetStock(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+
+	ctx := helper.WithRequestID(context.Background(), "request-1")
+	stock, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	require.Nil(t, err)
+
+	assert.Equal(t, "IDX:BBCA", stock.Symbol)
+	assert.Equal(t, "Bank Central Asia Tbk.", stock.Name)
+	assert.Equal(t, "Financials", stock.Sector)
+
+	requests := server.Requests(fakestock.StockPath)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "BBCA", requests[0].Query.Get("code"))
+	assert.Equal(t, "request-1", requests[0].RequestID)
+}
+
+func TestStockClientGetQuotes(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+
+	results := stockClient.GetQuotes(context.Background(), []string{"IDX:BBCA", "IDX:TLKM", "LSE:VOD"})
+	require.Len(t, results, 3)
+
+	require.Nil(t, results[0].Err)
+	assert.Equal(t, "IDX:BBCA", results[0].Quote.Stock)
+	assert.Equal(t, 9500.0, results[0].Quote.LastPrice)
+	assert.ErrorIs(t, results[1].Err, domainerr.ErrQuoteUnavailable)
+	assert.ErrorIs(t, results[2].Err, domainerr.ErrUnsupportedExchange)
+
+	// Both IDX stocks are asked for in one request
+	requests := server.Requests(fakestock.QuotesPath)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "BBCA,TLKM", requests[0].Query.Get("codes"))
+}
+
+func TestStockClientGetQuotesDuplicates(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+
+	stocks := []string{"BBCA", "IDX:BBCA", "IDX:TLKM", "BBCA"}
+	results := stockClient.GetQuotes(context.Background(), stocks)
+	require.Len(t, results, 4)
+
+	// Every stock resolving to BBCA gets the quote under its own symbol
+	for _, i := range []int{0, 1, 3} {
+		require.Nil(t, results[i].Err)
+		assert.Equal(t, stocks[i], results[i].Stock)
+		assert.Equal(t, stocks[i], results[i].Quote.Stock)
+		assert.Equal(t, 9500.0, results[i].Quote.LastPrice)
+	}
+	assert.ErrorIs(t, results[2].Err, domainerr.ErrQuoteUnavailable)
+
+	requests := server.Requests(fakestock.QuotesPath)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "BBCA,TLKM", requests[0].Query.Get("codes"))
+}
+
+func TestStockClientUnderwriters(t *testing.T) {
+	_, stockClient, _ := newFakeStockClient(t)
+
+	underwriters, err := stockClient.GetUnderwriters(context.Background())
+	require.Nil(t, err)
+	require.Len(t, underwriters, 2)
+	assert.Equal(t, "CC", underwriters[0].ID)
+	assert.Equal(t, "YP", underwriters[1].ID)
+
+	underwriters, err = stockClient.SearchUnderwriters(context.Background(), "mirae")
+	require.Nil(t, err)
+	require.Len(t, underwriters, 1)
+	assert.Equal(t, "YP", underwriters[0].ID)
+}
+
+func TestStockClientStrictDecoding(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+
+	server.Script(fakestock.StockPath,
+		fakestock.Step{Body: `{"message":"Stock found","data":{"code":"BBCA","name":"Bank Central Asia Tbk.","sector":"Financials","price":9500}}`},
+		fakestock.Step{Body: `{"message":"Stock found","data":{"code":"BBCA"}}`},
+		fakestock.Step{Body: `{"message":"Stock found","data":null} {}`},
+	)
+
+	for range 3 {
+		_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+		assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	}
+
+	// A malformed answer is not retried
+	assert.Len(t, server.Requests(fakestock.StockPath), 3)
+}
+
+func TestStockClientRetriesTransientErrors(t *testing.T) {
+	server, stockClient, breaker := newFakeStockClient(t)
+	server.Script(fakestock.StockPath,
+		fakestock.Step{Status: http.StatusServiceUnavailable},
+		fakestock.Step{Status: http.StatusBadGateway},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	require.Nil(t, err)
+
+	requests := server.Requests(fakestock.StockPath)
+	require.Len(t, requests, 3)
+
+	// Every attempt carries the same generated request ID
+	assert.NotEmpty(t, requests[0].RequestID)
+	assert.Equal(t, requests[0].RequestID, requests[2].RequestID)
+
+	// Only the final outcome reaches the breaker
+	assert.Equal(t, uint32(1), breaker.Counts().Requests)
+	assert.Equal(t, uint32(0), breaker.Counts().TotalFailures)
+}
+
+func TestStockClientDoesNotRetryClientErrors(t *testing.T) {
+	server, stockClient, breaker := newFakeStockClient(t)
+
+	for range 10 {
+		_, err := stockClient.GetStock(context.Background(), "IDX:XXXX")
+
+		var serviceErr *domainerr.ServiceError
+		require.True(t, errors.As(err, &serviceErr))
+		assert.Equal(t, http.StatusNotFound, serviceErr.Code)
+		assert.Equal(t, "Stock not found", serviceErr.Message)
+	}
+	assert.Len(t, server.Requests(fakestock.StockPath), 10)
+
+	// A 4xx answer never trips the breaker
+	assert.Equal(t, uint32(0), breaker.Counts().TotalFailures)
+}
+
+func TestStockClientServiceUnavailable(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+	server.Script(fakestock.StockPath,
+		fakestock.Step{Status: http.StatusBadGateway},
+		fakestock.Step{Status: http.StatusBadGateway},
+		fakestock.Step{Status: http.StatusBadGateway},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+	assert.Len(t, server.Requests(fakestock.StockPath), 3)
+}
+
+func TestStockClientConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	stockClient := client.NewStockClient(client.StockRoutes{entity.DefaultExchange: server.URL}, func(string) client.Breaker {
+		return circuit.NewCircuitBreaker(t.Name(), config.CircuitBreaker{})
+	})
+
+	_, err := stockClient.GetStock(context.Background(), "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrStockServiceUnavailable)
+}
+
+func TestStockClientTimeout(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+	server.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	assert.ErrorIs(t, err, domainerr.ErrServiceTimeout)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"stock_backend/internal/tracing"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	gatewayTraceID = "0af7651916cd43dd8448eb211c80319c"
+	gatewaySpanID  = "b7ad6b7169203331"
+)
+
+// spansOfTrace returns the finished spans of one trace by name
+func spansOfTrace(traceId trace.TraceID) map[string]tracetest.SpanStub {
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range spanExporter.GetSpans() {
+		if span.SpanContext.TraceID() == traceId {
+			spans[span.Name] = span
+		}
+	}
+	return spans
+}
+
+func TestTracingContinuesGatewayTrace(t *testing.T) {
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	// Miss the cache so the request reaches Postgres
+	redisCache.Invalidate(context.Background(), "watchlist:"+userId)
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+		"traceparent":   fmt.Sprintf("00-%s-%s-01", gatewayTraceID, gatewaySpanID),
+	}
+
+	_, statusCode, err := PerformRequest[map[string]any](nil, watchlistPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	traceId, err := trace.TraceIDFromHex(gatewayTraceID)
+	require.Nil(t, err)
+	spans := spansOfTrace(traceId)
+
+	server, ok := spans["GET "+watchlistPath]
+	require.True(t, ok)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, gatewaySpanID, server.Parent.SpanID().String())
+
+	service, ok := spans["WatchlistService.GetWatchlist"]
+	require.True(t, ok)
+	assert.Equal(t, server.SpanContext.SpanID(), service.Parent.SpanID())
+
+	_, ok = spans["CachedWatchlistRepository.GetWatchlistEntries"]
+	assert.True(t, ok)
+	_, ok = spans["WatchlistRepository.GetWatchlistEntries"]
+	assert.True(t, ok)
+
+	scopes := map[string]bool{}
+	for _, span := range spans {
+		scopes[span.InstrumentationScope.Name] = true
+	}
+	assert.True(t, scopes["github.com/XSAM/otelsql"])
+	assert.True(t, scopes["github.com/redis/go-redis/extra/redisotel"])
+}
+
+func TestTracingPropagatesToStockClient(t *testing.T) {
+	server, stockClient, _ := newFakeStockClient(t)
+
+	ctx, span := tracing.Start(context.Background(), t.Name())
+	_, err := stockClient.GetStock(ctx, "IDX:BBCA")
+	span.End()
+	require.Nil(t, err)
+
+	requests := server.Requests("/api/v1/stocks")
+	require.Len(t, requests, 1)
+
+	traceId := span.SpanContext().TraceID().String()
+	assert.True(t, strings.HasPrefix(requests[0].TraceParent, "00-"+traceId+"-"))
+
+	spans := spansOfTrace(span.SpanContext().TraceID())
+	client, ok := spans["StockClient.GetStock"]
+	require.True(t, ok)
+	assert.Equal(t, span.SpanContext().SpanID(), client.Parent.SpanID())
+}
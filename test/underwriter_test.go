@@ -0,0 +1,93 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/response"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const underwritersPath = "/api/v1/underwriters"
+
+func TestGetUnderwriters(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetUnderwritersResponse](nil, underwritersPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Underwriters retrieved successfully", result.Message)
+	assert.NotEmpty(t, result.Data)
+}
+
+func TestGetUnderwritersSearch(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s?search=%s", underwritersPath, "ciptadana")
+	result, statusCode, err := PerformRequest[*response.GetUnderwritersResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "KI", result.Data[0].ID)
+}
+
+func TestGetUnderwritersSearchIsLiteral(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	// As LIKE patterns these would match every name or fail on the trailing escape
+	for _, search := range []string{"%", "_", "%sekuritas", "\\"} {
+		path := fmt.Sprintf("%s?search=%s", underwritersPath, url.QueryEscape(search))
+		result, statusCode, err := PerformRequest[*response.GetUnderwritersResponse](nil, path, http.MethodGet, httpHeader)
+		require.Nil(t, err)
+
+		assert.Equal(t, http.StatusOK, statusCode, search)
+		assert.Empty(t, result.Data, search)
+	}
+
+	path := fmt.Sprintf("%s?search=%s", underwritersPath, url.QueryEscape("j.p."))
+	result, statusCode, err := PerformRequest[*response.GetUnderwritersResponse](nil, path, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "BK", result.Data[0].ID)
+}
+
+func TestGetUnderwritersUnauthorized(t *testing.T) {
+	httpHeader := map[string]string{
+		"Accept": "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, underwritersPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Equal(t, domainerr.ErrAuthorizationHeaderRequired.Error(), result.Message)
+}
+
+func TestSyncUnderwritersForbidden(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	_, statusCode, err := PerformRequest[*response.FailedResponse](nil, "/api/v1/admin/underwriters/sync", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
@@ -0,0 +1,169 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"stock_backend/internal/model/domainerr"
+	"stock_backend/internal/model/request"
+	"stock_backend/internal/model/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	watchlistSharePath        = "/api/v1/watchlists/shares"
+	publicWatchlistPath       = "/api/v1/public/watchlists"
+	sharedWatchlistStock      = "IDX:TLKM"
+	sharedWatchlistOtherStock = "IDX:ASII"
+)
+
+var (
+	shareId   string
+	shareSlug string
+)
+
+func TestCreateWatchlistShare(t *testing.T) {
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO watchlist (userid, stock, note, tags) VALUES ($1, $2, 'Dividend pick', '{income}'), ($1, $3, '', '{auto}')",
+		userId, sharedWatchlistStock, sharedWatchlistOtherStock)
+	require.Nil(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+	requestBody := request.CreateWatchlistShareRequest{
+		Title:     "Income ideas",
+		Tag:       "Income",
+		ExpiresAt: &expiresAt,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.CreateWatchlistShareResponse](requestBody, watchlistSharePath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "income", result.Data.Tag)
+	assert.Len(t, result.Data.Slug, 32)
+	assert.Equal(t, publicWatchlistPath+"/"+result.Data.Slug, result.Data.Path)
+	shareId = result.Data.ID.String()
+	shareSlug = result.Data.Slug
+}
+
+func TestCreateWatchlistShareExpired(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Hour)
+	requestBody := request.CreateWatchlistShareRequest{
+		ExpiresAt: &expiresAt,
+	}
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, watchlistSharePath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistShareExpiryInvalid.Error(), result.Message)
+}
+
+func TestGetPublicWatchlist(t *testing.T) {
+	httpHeader := map[string]string{
+		"Accept": "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", publicWatchlistPath, shareSlug)
+	result, statusCode, err := PerformRequest[*response.GetPublicWatchlistResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Income ideas", result.Data.Title)
+	require.Len(t, result.Data.Stocks, 1)
+	assert.Equal(t, sharedWatchlistStock, result.Data.Stocks[0].Stock)
+	assert.Equal(t, "Dividend pick", result.Data.Stocks[0].Note)
+}
+
+func TestGetWatchlistSharesViewCount(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.GetWatchlistSharesResponse](nil, watchlistSharePath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, int64(1), result.Data[0].ViewCount)
+	assert.NotNil(t, result.Data[0].LastViewedAt)
+}
+
+func TestRotateWatchlistShare(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s/rotate", watchlistSharePath, shareId)
+	result, statusCode, err := PerformRequest[*response.RotateWatchlistShareResponse](nil, url, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEqual(t, shareSlug, result.Data.Slug)
+
+	oldUrl := fmt.Sprintf("%s/%s", publicWatchlistPath, shareSlug)
+	_, statusCode, err = PerformRequest[*response.FailedResponse](nil, oldUrl, http.MethodGet, map[string]string{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	shareSlug = result.Data.Slug
+}
+
+func TestRevokeWatchlistShare(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", watchlistSharePath, shareId)
+	result, statusCode, err := PerformRequest[*response.RevokeWatchlistShareResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Share link revoked successfully", result.Message)
+
+	publicUrl := fmt.Sprintf("%s/%s", publicWatchlistPath, shareSlug)
+	failed, statusCode, err := PerformRequest[*response.FailedResponse](nil, publicUrl, http.MethodGet, map[string]string{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistShareNotFound.Error(), failed.Message)
+}
+
+func TestRevokeWatchlistShareNotFound(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/%s", watchlistSharePath, shareId)
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistShareNotFound.Error(), result.Message)
+
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	_, err = db.Exec("DELETE FROM watchlist WHERE userid = $1 AND stock IN ($2, $3)", userId, sharedWatchlistStock, sharedWatchlistOtherStock)
+	require.Nil(t, err)
+}
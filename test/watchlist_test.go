@@ -1,13 +1,16 @@
 package test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"stock_backend/internal/client/fakestock"
 	"stock_backend/internal/model/domainerr"
 	"stock_backend/internal/model/request"
 	"stock_backend/internal/model/response"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,7 +36,41 @@ func TestAddWatchlist(t *testing.T) {
 	require.Nil(t, err)
 
 	assert.Equal(t, http.StatusCreated, statusCode)
-	assert.Equal(t, "Successfully added NOBU to watchlist", result.Message)
+	assert.Equal(t, "Successfully added IDX:NOBU to watchlist", result.Message)
+}
+
+func TestAddWatchlistInvalidSymbol(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	for _, stock := range []string{"BB", "IDX:BBCAX", "LSE:VOD"} {
+		requestBody := request.AddWatchlistRequest{
+			Stock: stock,
+		}
+
+		result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, addWatchlistPath, http.MethodPost, httpHeader)
+		require.Nil(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode, stock)
+		assert.Equal(t, "Stock must be a symbol such as IDX:BBCA", result.Message, stock)
+	}
+}
+
+func TestRemoveFromWatchlistUnsupportedExchange(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := fmt.Sprintf("%s/stocks/%s", watchlistPath, "LSE:VOD")
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrUnsupportedExchange.Error(), result.Message)
 }
 
 func TestAddWatchListUnauthorized(t *testing.T) {
@@ -99,7 +136,278 @@ func TestGetWatchlist(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, statusCode)
 	assert.Equal(t, "Watchlist retrieved successfully", result.Message)
-	assert.Equal(t, "NOBU", result.Stocks[0])
+	assert.Equal(t, "IDX:NOBU", result.Stocks[0].Stock)
+	assert.False(t, result.Stocks[0].AddedAt.IsZero())
+}
+
+func TestGetWatchlistOmitsUserId(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	body, res, err := PerformRawRequest("", watchlistPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var result struct {
+		Stocks []map[string]any `json:"stocks"`
+	}
+	require.Nil(t, json.Unmarshal(body, &result))
+	require.NotEmpty(t, result.Stocks)
+	assert.NotContains(t, result.Stocks[0], "user_id")
+}
+
+func TestGetWatchlistCached(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	userId, err := GetUserIDByEmail(email)
+	require.Nil(t, err)
+
+	// A row written behind the repository's back is not seen until the cache is invalidated
+	_, err = db.Exec("INSERT INTO watchlist (userid, stock) VALUES ($1, 'IDX:TLKM')", userId)
+	require.Nil(t, err)
+
+	result, statusCode, err := PerformRequest[*response.GetWatchlistResponse](nil, watchlistPath, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Stocks, 1)
+	assert.Equal(t, "IDX:NOBU", result.Stocks[0].Stock)
+
+	_, err = db.Exec("DELETE FROM watchlist WHERE userid = $1 AND stock = 'IDX:TLKM'", userId)
+	require.Nil(t, err)
+}
+
+func TestUpdateWatchlist(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	note := "Digital banking play"
+	targetBuy := decimal.NewFromInt(400)
+	targetSell := decimal.RequireFromString("650.25")
+	requestBody := request.UpdateWatchlistRequest{
+		Note:       &note,
+		TargetBuy:  &targetBuy,
+		TargetSell: &targetSell,
+		Tags:       []string{"Banking", " banking", "growth"},
+	}
+
+	url := addWatchlistPath + "/NOBU"
+	result, statusCode, err := PerformRequest[*response.UpdateWatchlistResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, note, result.Data.Note)
+	require.NotNil(t, result.Data.TargetBuy)
+	assert.True(t, targetBuy.Equal(*result.Data.TargetBuy))
+	require.NotNil(t, result.Data.TargetSell)
+	assert.True(t, targetSell.Equal(*result.Data.TargetSell))
+	assert.Equal(t, []string{"banking", "growth"}, result.Data.Tags)
+}
+
+func TestUpdateWatchlistClearTarget(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	targetBuy := decimal.Zero
+	requestBody := request.UpdateWatchlistRequest{
+		TargetBuy: &targetBuy,
+	}
+
+	url := addWatchlistPath + "/NOBU"
+	result, statusCode, err := PerformRequest[*response.UpdateWatchlistResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Nil(t, result.Data.TargetBuy)
+	assert.NotNil(t, result.Data.TargetSell)
+	assert.Equal(t, "Digital banking play", result.Data.Note)
+}
+
+func TestUpdateWatchlistTargetInvalid(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	targetBuy := decimal.NewFromInt(700)
+	requestBody := request.UpdateWatchlistRequest{
+		TargetBuy: &targetBuy,
+	}
+
+	url := addWatchlistPath + "/NOBU"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistTargetInvalid.Error(), result.Message)
+}
+
+func TestUpdateWatchlistTargetNegative(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	targetSell := decimal.NewFromInt(-1)
+	requestBody := request.UpdateWatchlistRequest{
+		TargetSell: &targetSell,
+	}
+
+	url := addWatchlistPath + "/NOBU"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistTargetNegative.Error(), result.Message)
+}
+
+func TestUpdateWatchlistNotFound(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	note := "Not in the watchlist"
+	requestBody := request.UpdateWatchlistRequest{
+		Note: &note,
+	}
+
+	url := addWatchlistPath + "/TLKM"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, url, http.MethodPatch, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistNotFound.Error(), result.Message)
+}
+
+func TestGetWatchlistByTag(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := watchlistPath + "?tag=Growth&sort=stock"
+	result, statusCode, err := PerformRequest[*response.GetWatchlistResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Stocks, 1)
+	assert.Equal(t, "IDX:NOBU", result.Stocks[0].Stock)
+
+	url = watchlistPath + "?tag=dividend"
+	_, statusCode, err = PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestGetWatchlistInvalidSort(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := watchlistPath + "?sort=price"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrWatchlistSortInvalid.Error(), result.Message)
+}
+
+func TestGetWatchlistWithQuote(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := watchlistPath + "?include=quote"
+	result, statusCode, err := PerformRequest[*response.GetWatchlistQuotesResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Watchlist retrieved successfully", result.Message)
+	require.Len(t, result.Stocks, 1)
+	assert.Equal(t, "IDX:NOBU", result.Stocks[0].Stock)
+	assert.Empty(t, result.Stocks[0].QuoteError)
+
+	// The quote served by the fake stock service in init.go
+	require.NotNil(t, result.Stocks[0].Quote)
+	assert.Equal(t, 525.0, result.Stocks[0].Quote.LastPrice)
+	assert.Equal(t, -5.0, result.Stocks[0].Quote.Change)
+	assert.Equal(t, int64(310000), result.Stocks[0].Quote.Volume)
+}
+
+func TestGetWatchlistWithQuoteUnavailable(t *testing.T) {
+	// Listed by the stock service, but it has no quote for it
+	stockServer.AddStocks(fakestock.Stock{Code: "GOTO", Name: "GoTo Gojek Tokopedia Tbk.", Sector: "Technology"})
+
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.AddWatchlistRequest{
+		Stock: "GOTO",
+	}
+	_, statusCode, err := PerformRequest[*response.AddWatchlistResponse](requestBody, addWatchlistPath, http.MethodPost, httpHeader)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	t.Cleanup(func() {
+		url := fmt.Sprintf("%s/stocks/%s", watchlistPath, "GOTO")
+		_, _, err := PerformRequest[*response.RemoveWatchlistResponse](nil, url, http.MethodDelete, httpHeader)
+		require.Nil(t, err)
+	})
+
+	url := watchlistPath + "?include=quote"
+	result, statusCode, err := PerformRequest[*response.GetWatchlistQuotesResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Stocks, 2)
+
+	stocks := map[string]response.WatchlistStockQuote{}
+	for _, stock := range result.Stocks {
+		stocks[stock.Stock] = stock
+	}
+
+	unavailable := stocks["IDX:GOTO"]
+	assert.Nil(t, unavailable.Quote)
+	assert.Equal(t, domainerr.ErrQuoteUnavailable.Error(), unavailable.QuoteError)
+
+	// One missing quote doesn't fail the others
+	require.NotNil(t, stocks["IDX:NOBU"].Quote)
+	assert.Equal(t, 525.0, stocks["IDX:NOBU"].Quote.LastPrice)
+	assert.Empty(t, stocks["IDX:NOBU"].QuoteError)
+}
+
+func TestGetWatchlistInvalidInclude(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	url := watchlistPath + "?include=news"
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, url, http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrInvalidInclude.Error(), result.Message)
 }
 
 func TestGetWatchlistUnauthorized(t *testing.T) {
@@ -127,7 +435,7 @@ func TestRemoveFromWatchlist(t *testing.T) {
 	require.Nil(t, err)
 
 	assert.Equal(t, http.StatusOK, statusCode)
-	assert.Equal(t, "Successfully removed NOBU from watchlist", result.Message)
+	assert.Equal(t, "Successfully removed IDX:NOBU from watchlist", result.Message)
 }
 
 func TestRemoveFromWatchlistUnauthorized(t *testing.T) {
@@ -172,3 +480,144 @@ func TestGetWatchlistNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, statusCode)
 	assert.Equal(t, domainerr.ErrWatchlistNotFound.Error(), result.Message)
 }
+
+func TestImportWatchlistCSV(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "text/csv",
+		"Accept":        "application/json",
+	}
+
+	body, res, err := PerformRawRequest("stock\nNOBU\nidx:nobu\nAB\n", watchlistPath+"/import", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var result response.ImportResponse
+	require.Nil(t, json.Unmarshal(body, &result))
+
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Rows, 3)
+	assert.Equal(t, response.ImportRowImported, result.Rows[0].Status)
+	assert.Equal(t, domainerr.ErrImportDuplicateRow.Error(), result.Rows[1].Error)
+	assert.Equal(t, domainerr.ErrInvalidSymbol.Error(), result.Rows[2].Error)
+}
+
+func TestImportWatchlistAlreadyExists(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := map[string][]string{"stocks": {"NOBU"}}
+	result, statusCode, err := PerformRequest[*response.ImportResponse](requestBody, watchlistPath+"/import", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, domainerr.ErrWatchlistDuplicate.Error(), result.Rows[0].Error)
+}
+
+func TestImportWatchlistEmpty(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := map[string][]string{"stocks": {}}
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, watchlistPath+"/import", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrImportEmpty.Error(), result.Message)
+}
+
+func TestExportWatchlistCSV(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+
+	body, res, err := PerformRawRequest("", watchlistPath+"/export?format=csv", http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Contains(t, res.Header.Get("Content-Disposition"), "watchlist.csv")
+	assert.Equal(t, "stock\nIDX:NOBU\n", string(body))
+}
+
+func TestExportWatchlistInvalidFormat(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/json",
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](nil, watchlistPath+"/export?format=xml", http.MethodGet, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, domainerr.ErrExportFormatInvalid.Error(), result.Message)
+}
+
+func TestAddWatchlistBatch(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.BatchWatchlistRequest{
+		Stocks: []string{"NOBU", "BBCA", "LSE:VOD"},
+	}
+
+	result, statusCode, err := PerformRequest[*response.BatchResponse](requestBody, watchlistPath+"/stocks:batch", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, result.Succeeded)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, domainerr.ErrWatchlistDuplicate.Error(), result.Items[0].Error)
+	assert.Equal(t, response.BatchItemAdded, result.Items[1].Status)
+	assert.Equal(t, domainerr.ErrUnsupportedExchange.Error(), result.Items[2].Error)
+}
+
+func TestAddWatchlistBatchEmpty(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.BatchWatchlistRequest{
+		Stocks: []string{},
+	}
+
+	result, statusCode, err := PerformRequest[*response.FailedResponse](requestBody, watchlistPath+"/stocks:batch", http.MethodPost, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "Stocks must be greater than or equal to 1", result.Message)
+}
+
+func TestRemoveWatchlistBatch(t *testing.T) {
+	httpHeader := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+	}
+
+	requestBody := request.BatchWatchlistRequest{
+		Stocks: []string{"NOBU", "BBCA", "TLKM"},
+	}
+
+	result, statusCode, err := PerformRequest[*response.BatchResponse](requestBody, watchlistPath+"/stocks:batch", http.MethodDelete, httpHeader)
+	require.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, response.BatchItemRemoved, result.Items[0].Status)
+	assert.Equal(t, domainerr.ErrWatchlistNotFound.Error(), result.Items[2].Error)
+}